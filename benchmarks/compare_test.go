@@ -0,0 +1,125 @@
+// Package benchmarks -- сравнение BaseLogger с zap, logrus и zerolog: напрямую и через адаптеры logger.Levelable.
+// Отдельный модуль, чтобы зависимости сравниваемых логгеров не попадали в основной.
+//
+//	cd benchmarks && go test -bench . -benchmem
+package benchmarks
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/Arhat109/logger/pkg/adapters/logrusadapter"
+	"github.com/Arhat109/logger/pkg/adapters/zapadapter"
+	"github.com/Arhat109/logger/pkg/adapters/zerologadapter"
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+const message = "any info message"
+
+// discard -- вывод в никуда. Не io.Discard: его log.Logger распознает и пропускает форматирование
+type discard struct{}
+
+func (discard) Write(data []byte) (int, error) { return len(data), nil }
+
+var out io.Writer = discard{}
+
+// все логгеры пишут json со временем и уровнем в никуда, без места вызова
+func newBaseLogger(format string) *logger.BaseLogger {
+	var err error
+	lgr := (&logger.BaseLogger{}).Init(&logger.LogConfig{
+		Out:        "devnul",
+		Format:     format,
+		Flags:      logger.LogDate | logger.LogTime | logger.LogMicroSeconds,
+		Level:      logger.LogInfoLevel,
+		TimeFormat: logger.TimeFormatRFC3339Nano,
+	}, &err)
+	lgr.Out = out
+	return lgr
+}
+
+func newZap() *zap.Logger {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(cfg), zapcore.AddSync(out), zap.InfoLevel))
+}
+
+func newLogrus() *logrus.Logger {
+	lr := logrus.New()
+	lr.Out = out
+	lr.Formatter = &logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	lr.Level = logrus.InfoLevel
+	return lr
+}
+
+func newZerolog() zerolog.Logger {
+	return zerolog.New(out).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+}
+
+// Benchmark_Message -- одно сообщение без полей
+func Benchmark_Message(b *testing.B) {
+	zl, lr, zr := newZap(), newLogrus(), newZerolog()
+	stdLgr := log.New(out, "", log.LstdFlags|log.Lmicroseconds)
+
+	run(b, "BaseLogger/json", newBaseLogger(logger.DefLoggerJson).Info)
+	run(b, "BaseLogger/logfmt", newBaseLogger(logger.DefLoggerLogfmt).Info)
+	run(b, "BaseLogger/text", newBaseLogger(logger.DefLoggerText).Info)
+	b.Run("zap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			zl.Info(message)
+		}
+	})
+	run(b, "zap/adapter", zapadapter.New(zl).Info)
+	b.Run("logrus", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			lr.Info(message)
+		}
+	})
+	run(b, "logrus/adapter", logrusadapter.New(lr).Info)
+	b.Run("zerolog", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			zr.Info().Msg(message)
+		}
+	})
+	run(b, "zerolog/adapter", zerologadapter.New(zr).Info)
+	run(b, "stdlog", stdLgr.Printf)
+}
+
+// Benchmark_Fields -- сообщение с полями логгера, добавленными заранее через With()
+func Benchmark_Fields(b *testing.B) {
+	fields := []logger.Field{{Key: "user", Value: "alice"}, {Key: "request", Value: 42}, {Key: "elapsed", Value: 1.5}}
+	zl, lr, zr := newZap(), newLogrus(), newZerolog()
+
+	run(b, "BaseLogger/json", newBaseLogger(logger.DefLoggerJson).With(fields...).Info)
+	run(b, "BaseLogger/logfmt", newBaseLogger(logger.DefLoggerLogfmt).With(fields...).Info)
+	run(b, "zap/adapter", zapadapter.New(zl).With(fields...).Info)
+	run(b, "logrus/adapter", logrusadapter.New(lr).With(fields...).Info)
+	run(b, "zerolog/adapter", zerologadapter.New(zr).With(fields...).Info)
+}
+
+// Benchmark_Disabled -- цена вызова запрещенного уровня
+func Benchmark_Disabled(b *testing.B) {
+	run(b, "BaseLogger", newBaseLogger(logger.DefLoggerJson).Debug)
+	run(b, "zap/adapter", zapadapter.New(newZap()).Debug)
+	run(b, "logrus/adapter", logrusadapter.New(newLogrus()).Debug)
+	run(b, "zerolog/adapter", zerologadapter.New(newZerolog()).Debug)
+}
+
+// run -- подтест для метода уровня с сигнатурой logger.Levelable
+func run(b *testing.B, name string, method func(msg string, args ...any)) {
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			method(message)
+		}
+	})
+}
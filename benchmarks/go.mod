@@ -0,0 +1,29 @@
+module github.com/Arhat109/logger/benchmarks
+
+go 1.19
+
+require (
+	github.com/Arhat109/logger v0.0.0
+	github.com/Arhat109/logger/pkg/adapters/logrusadapter v0.0.0
+	github.com/Arhat109/logger/pkg/adapters/zapadapter v0.0.0
+	github.com/Arhat109/logger/pkg/adapters/zerologadapter v0.0.0
+	github.com/rs/zerolog v1.33.0
+	github.com/sirupsen/logrus v1.9.3
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/Arhat109/logger => ../
+	github.com/Arhat109/logger/pkg/adapters/logrusadapter => ../pkg/adapters/logrusadapter
+	github.com/Arhat109/logger/pkg/adapters/zapadapter => ../pkg/adapters/zapadapter
+	github.com/Arhat109/logger/pkg/adapters/zerologadapter => ../pkg/adapters/zerologadapter
+)
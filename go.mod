@@ -3,8 +3,11 @@ module github.com/Arhat109/logger
 go 1.19
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/google/uuid v1.3.0
 	google.golang.org/grpc v1.53.0
+	google.golang.org/protobuf v1.28.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -13,5 +16,4 @@ require (
 	golang.org/x/sys v0.4.0 // indirect
 	golang.org/x/text v0.6.0 // indirect
 	google.golang.org/genproto v0.0.0-20230110181048-76db0878b65f // indirect
-	google.golang.org/protobuf v1.28.1 // indirect
 )
@@ -0,0 +1,17 @@
+module github.com/Arhat109/logger/pkg/adapters/logrusadapter
+
+go 1.19
+
+require (
+	github.com/Arhat109/logger v0.0.0
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/Arhat109/logger => ../../..
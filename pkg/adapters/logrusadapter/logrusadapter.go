@@ -0,0 +1,164 @@
+// Package logrusadapter -- github.com/sirupsen/logrus за интерфейсами logger.Levelable и logger.Loggable.
+// Отдельный модуль, чтобы зависимости logrus не попадали в основной пакет.
+//
+// logrus не умеет пропускать кадры при ReportCaller и показал бы место вызова внутри адаптера,
+// поэтому место вызова добавляется самим адаптером полем "caller" @see WithCaller().
+package logrusadapter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+// CallerKey -- ключ поля места вызова file.go:12
+const CallerKey = "caller"
+
+// Logger -- адаптер logrus
+type Logger struct {
+	entry    *logrus.Entry
+	isCaller bool
+	traceKey string
+}
+
+// Option -- настройка адаптера
+type Option func(lgr *Logger)
+
+// WithCaller -- добавлять к записям поле "caller" с местом вызова методов адаптера
+func WithCaller() Option {
+	return func(lgr *Logger) { lgr.isCaller = true }
+}
+
+// WithTraceKey -- ключ трассировки в контексте для Ctx() и поля записи, по умолчанию logger.CtxTraceId
+func WithTraceKey(key string) Option {
+	return func(lgr *Logger) { lgr.traceKey = key }
+}
+
+// New -- адаптер поверх настроенного logrus логгера
+func New(lr *logrus.Logger, opts ...Option) *Logger {
+	lgr := &Logger{entry: logrus.NewEntry(lr), traceKey: logger.CtxTraceId}
+	for _, opt := range opts {
+		opt(lgr)
+	}
+	return lgr
+}
+
+// Logrus -- запись logrus с полями адаптера
+func (lgr *Logger) Logrus() *logrus.Entry { return lgr.entry }
+
+// GetLevel -- уровень logrus в шкале logger.LogPanicLevel..LogDebugLevel
+func (lgr *Logger) GetLevel() int { return FromLogrusLevel(lgr.entry.Logger.GetLevel()) }
+
+// Outlog -- запись с заданной глубины вызовов @see logger.Loggable
+func (lgr *Logger) Outlog(depth int, now time.Time, level, message string) {
+	lgr.output(depth+1, ToLogrusLevel(logger.ToLevel(level)), now, message, nil)
+}
+
+func (lgr *Logger) Debug(msg string, args ...any) { lgr.log(logrus.DebugLevel, msg, args, nil) }
+func (lgr *Logger) Info(msg string, args ...any)  { lgr.log(logrus.InfoLevel, msg, args, nil) }
+func (lgr *Logger) Warn(msg string, args ...any)  { lgr.log(logrus.WarnLevel, msg, args, nil) }
+func (lgr *Logger) Error(msg string, args ...any) { lgr.log(logrus.ErrorLevel, msg, args, nil) }
+func (lgr *Logger) Fatal(msg string, args ...any) { lgr.log(logrus.FatalLevel, msg, args, nil) }
+func (lgr *Logger) Panic(msg string, args ...any) { lgr.log(logrus.PanicLevel, msg, args, nil) }
+
+func (lgr *Logger) WarnE(err error, msg string, args ...any) {
+	lgr.log(logrus.WarnLevel, msg, args, err)
+}
+func (lgr *Logger) ErrorE(err error, msg string, args ...any) {
+	lgr.log(logrus.ErrorLevel, msg, args, err)
+}
+
+// With -- адаптер с добавленными полями @see logger.BaseLogger.With()
+func (lgr *Logger) With(fields ...logger.Field) *Logger {
+	child := *lgr
+	child.entry = lgr.entry.WithFields(LogrusFields(fields))
+	return &child
+}
+
+// Ctx -- адаптер с полем трассировки из контекста, если оно там есть, и самим контекстом в записи
+func (lgr *Logger) Ctx(ctx context.Context) *Logger {
+	child := *lgr
+	child.entry = lgr.entry.WithContext(ctx)
+	if traceId := logger.TraceIdFromContext(ctx, lgr.traceKey); traceId != "" {
+		child.entry = child.entry.WithField(lgr.traceKey, traceId)
+	}
+	return &child
+}
+
+// log -- глубина 2: над log() метод уровня, над ним -- место вызова
+func (lgr *Logger) log(level logrus.Level, msg string, args []any, err error) {
+	if lgr.entry.Logger.IsLevelEnabled(level) || level <= logrus.FatalLevel {
+		lgr.output(2, level, time.Now(), fmt.Sprintf(msg, args...), err)
+	}
+}
+
+// output -- запись с заданным временем. Fatal и Panic -- как в logrus: завершение процесса и паника
+func (lgr *Logger) output(depth int, level logrus.Level, now time.Time, message string, err error) {
+	entry := lgr.entry.WithTime(now)
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	if lgr.isCaller {
+		if _, file, line, ok := runtime.Caller(depth + 1); ok {
+			entry = entry.WithField(CallerKey, filepath.Base(file)+":"+strconv.Itoa(line))
+		}
+	}
+	switch level {
+	case logrus.FatalLevel:
+		entry.Fatal(message)
+	case logrus.PanicLevel:
+		entry.Panic(message)
+	default:
+		entry.Log(level, message)
+	}
+}
+
+// LogrusFields -- поля логгера полями logrus
+func LogrusFields(fields []logger.Field) logrus.Fields {
+	lf := make(logrus.Fields, len(fields))
+	for _, field := range fields {
+		lf[field.Key] = field.Value
+	}
+	return lf
+}
+
+// ToLogrusLevel -- уровень logger.LogPanicLevel..LogDebugLevel уровнем logrus
+func ToLogrusLevel(level int) logrus.Level {
+	switch {
+	case level <= logger.LogPanicLevel:
+		return logrus.PanicLevel
+	case level <= logger.LogFatalLevel:
+		return logrus.FatalLevel
+	case level <= logger.LogErrorLevel:
+		return logrus.ErrorLevel
+	case level <= logger.LogWarnLevel:
+		return logrus.WarnLevel
+	case level <= logger.LogInfoLevel:
+		return logrus.InfoLevel
+	}
+	return logrus.DebugLevel
+}
+
+// FromLogrusLevel -- уровень logrus в шкале logger.LogPanicLevel..LogDebugLevel, Trace -- как Debug
+func FromLogrusLevel(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		return logger.LogPanicLevel
+	case logrus.FatalLevel:
+		return logger.LogFatalLevel
+	case logrus.ErrorLevel:
+		return logger.LogErrorLevel
+	case logrus.WarnLevel:
+		return logger.LogWarnLevel
+	case logrus.InfoLevel:
+		return logger.LogInfoLevel
+	}
+	return logger.LogDebugLevel
+}
@@ -0,0 +1,73 @@
+package logrusadapter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/Arhat109/logger/pkg/adapters/logrusadapter"
+	loghttp "github.com/Arhat109/logger/pkg/http"
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func thisLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestAdapter(t *testing.T) {
+	lr, hook := test.NewNullLogger()
+	lr.SetLevel(logrus.InfoLevel)
+	lgr := logrusadapter.New(lr, logrusadapter.WithCaller())
+	var _ logger.Levelable = lgr
+	var _ logger.Loggable = lgr
+
+	if lgr.GetLevel() != logger.LogInfoLevel {
+		t.Errorf("GetLevel() = %d", lgr.GetLevel())
+	}
+
+	line := thisLine() + 1
+	lgr.Info("hello %d", 1)
+	lgr.Debug("hidden")
+	ctx := context.WithValue(context.Background(), logger.CtxTraceId, "abc")
+	lgr.Ctx(ctx).ErrorE(errors.New("boom"), "failed")
+	lgr.Outlog(0, time.Now(), logger.LogWarnPrefix, "direct")
+
+	entries := hook.AllEntries()
+	if len(entries) != 3 {
+		t.Fatalf("want 3 entries, got %d", len(entries))
+	}
+	if entries[0].Message != "hello 1" || entries[0].Data[logrusadapter.CallerKey] != "logrusadapter_test.go:"+strconv.Itoa(line) {
+		t.Errorf("first entry: %s %v", entries[0].Message, entries[0].Data)
+	}
+	if data := entries[1].Data; data[logger.CtxTraceId] != "abc" || data[logrus.ErrorKey].(error).Error() != "boom" {
+		t.Errorf("context fields: %v", data)
+	}
+	if entries[2].Level != logrus.WarnLevel || entries[2].Data[logrusadapter.CallerKey] != "logrusadapter_test.go:"+strconv.Itoa(line+4) {
+		t.Errorf("Outlog(): %v %v", entries[2].Level, entries[2].Data)
+	}
+}
+
+// TestCtxFromMiddleware -- идент трассировки, положенный промежуточным обработчиком http, виден адаптеру
+func TestCtxFromMiddleware(t *testing.T) {
+	lr, hook := test.NewNullLogger()
+	lgr := logrusadapter.New(lr)
+	handler := loghttp.TracingMiddleware(&logger.BaseLogger{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lgr.Ctx(r.Context()).Info("handled")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(loghttp.HeaderRequestId, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if entries := hook.AllEntries(); len(entries) != 1 || entries[0].Data[logger.CtxTraceId] != "req-42" {
+		t.Errorf("entries: %v", entries)
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/Arhat109/logger/pkg/adapters/zapadapter
+
+go 1.19
+
+require (
+	github.com/Arhat109/logger v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/Arhat109/logger => ../../..
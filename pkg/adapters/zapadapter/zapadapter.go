@@ -0,0 +1,144 @@
+// Package zapadapter -- go.uber.org/zap за интерфейсами logger.Levelable и logger.Loggable.
+// Отдельный модуль, чтобы зависимости zap не попадали в основной пакет.
+//
+//	zl, _ := zap.NewProduction()
+//	var lgr logger.Levelable = zapadapter.New(zl)
+package zapadapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+// Logger -- адаптер zap. Место вызова (zap.AddCaller) -- код, вызвавший методы адаптера.
+type Logger struct {
+	// zl -- с пропуском кадров адаптера: output(), log() и метода уровня
+	zl       *zap.Logger
+	traceKey string
+}
+
+// New -- адаптер поверх настроенного zap логгера
+func New(zl *zap.Logger) *Logger {
+	return &Logger{zl: zl.WithOptions(zap.AddCallerSkip(3)), traceKey: logger.CtxTraceId}
+}
+
+// WithTraceKey -- ключ трассировки в контексте для Ctx() и поля записи, по умолчанию logger.CtxTraceId
+func (lgr *Logger) WithTraceKey(key string) *Logger {
+	child := *lgr
+	child.traceKey = key
+	return &child
+}
+
+// Zap -- исходный zap логгер (без пропуска кадров адаптера)
+func (lgr *Logger) Zap() *zap.Logger { return lgr.zl.WithOptions(zap.AddCallerSkip(-3)) }
+
+// GetLevel -- наименьший разрешенный в zap уровень в шкале logger.LogPanicLevel..LogDebugLevel
+func (lgr *Logger) GetLevel() int { return FromZapLevel(zapcore.LevelOf(lgr.zl.Core())) }
+
+// Outlog -- запись с заданной глубины вызовов @see logger.Loggable
+func (lgr *Logger) Outlog(depth int, now time.Time, level, message string) {
+	zl := lgr.zl
+	if depth != 1 { // глубина 1 -- как у методов уровня: над Outlog() метод, над ним -- место вызова
+		zl = zl.WithOptions(zap.AddCallerSkip(depth - 1))
+	}
+	output(zl, ToZapLevel(logger.ToLevel(level)), now, message)
+}
+
+func (lgr *Logger) Debug(msg string, args ...any) { lgr.log(zapcore.DebugLevel, msg, args) }
+func (lgr *Logger) Info(msg string, args ...any)  { lgr.log(zapcore.InfoLevel, msg, args) }
+func (lgr *Logger) Warn(msg string, args ...any)  { lgr.log(zapcore.WarnLevel, msg, args) }
+func (lgr *Logger) Error(msg string, args ...any) { lgr.log(zapcore.ErrorLevel, msg, args) }
+func (lgr *Logger) Fatal(msg string, args ...any) { lgr.log(zapcore.FatalLevel, msg, args) }
+func (lgr *Logger) Panic(msg string, args ...any) { lgr.log(zapcore.PanicLevel, msg, args) }
+
+func (lgr *Logger) WarnE(err error, msg string, args ...any) {
+	lgr.log(zapcore.WarnLevel, msg, args, zap.Error(err))
+}
+func (lgr *Logger) ErrorE(err error, msg string, args ...any) {
+	lgr.log(zapcore.ErrorLevel, msg, args, zap.Error(err))
+}
+
+// With -- адаптер с добавленными полями @see logger.BaseLogger.With()
+func (lgr *Logger) With(fields ...logger.Field) *Logger {
+	child := *lgr
+	child.zl = lgr.zl.With(ZapFields(fields)...)
+	return &child
+}
+
+// Ctx -- адаптер с полем трассировки из контекста, если оно там есть
+func (lgr *Logger) Ctx(ctx context.Context) *Logger {
+	if traceId := logger.TraceIdFromContext(ctx, lgr.traceKey); traceId != "" {
+		return lgr.With(logger.Field{Key: lgr.traceKey, Value: traceId})
+	}
+	return lgr
+}
+
+// log -- сообщение форматируется, только если уровень разрешен
+func (lgr *Logger) log(level zapcore.Level, msg string, args []any, fields ...zap.Field) {
+	if lgr.zl.Core().Enabled(level) || level > zapcore.ErrorLevel {
+		output(lgr.zl, level, time.Now(), fmt.Sprintf(msg, args...), fields...)
+	}
+}
+
+// output -- запись с заданным временем. Fatal и Panic -- как в zap: завершение процесса и паника
+func output(zl *zap.Logger, level zapcore.Level, now time.Time, message string, fields ...zap.Field) {
+	if entry := zl.Check(level, message); entry != nil {
+		entry.Time = now
+		entry.Write(fields...)
+	}
+}
+
+// ZapFields -- поля логгера полями zap
+func ZapFields(fields []logger.Field) []zap.Field {
+	zf := make([]zap.Field, len(fields))
+	for i, field := range fields {
+		if err, ok := field.Value.(error); ok {
+			zf[i] = zap.NamedError(field.Key, err)
+			continue
+		}
+		zf[i] = zap.Any(field.Key, field.Value)
+	}
+	return zf
+}
+
+// ToZapLevel -- уровень logger.LogPanicLevel..LogDebugLevel уровнем zap
+func ToZapLevel(level int) zapcore.Level {
+	switch {
+	case level <= logger.LogPanicLevel:
+		return zapcore.PanicLevel
+	case level <= logger.LogFatalLevel:
+		return zapcore.FatalLevel
+	case level <= logger.LogErrorLevel:
+		return zapcore.ErrorLevel
+	case level <= logger.LogWarnLevel:
+		return zapcore.WarnLevel
+	case level <= logger.LogInfoLevel:
+		return zapcore.InfoLevel
+	}
+	return zapcore.DebugLevel
+}
+
+// FromZapLevel -- уровень zap в шкале logger.LogPanicLevel..LogDebugLevel
+func FromZapLevel(level zapcore.Level) int {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return logger.LogDebugLevel
+	case level == zapcore.InfoLevel:
+		return logger.LogInfoLevel
+	case level == zapcore.WarnLevel:
+		return logger.LogWarnLevel
+	case level <= zapcore.DPanicLevel:
+		return logger.LogErrorLevel
+	case level == zapcore.PanicLevel:
+		return logger.LogPanicLevel
+	case level == zapcore.FatalLevel:
+		return logger.LogFatalLevel
+	}
+	return logger.LogNoneLevel
+}
@@ -0,0 +1,72 @@
+package zapadapter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/Arhat109/logger/pkg/adapters/zapadapter"
+	loghttp "github.com/Arhat109/logger/pkg/http"
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func thisLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestAdapter(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	lgr := zapadapter.New(zap.New(core, zap.AddCaller()))
+	var _ logger.Levelable = lgr
+	var _ logger.Loggable = lgr
+
+	if lgr.GetLevel() != logger.LogInfoLevel {
+		t.Errorf("GetLevel() = %d", lgr.GetLevel())
+	}
+
+	line := thisLine() + 1
+	lgr.Info("hello %d", 1)
+	lgr.Debug("hidden")
+	ctx := context.WithValue(context.Background(), logger.CtxTraceId, "abc")
+	lgr.Ctx(ctx).ErrorE(errors.New("boom"), "failed")
+	lgr.Outlog(0, zapcore.DefaultClock.Now(), logger.LogWarnPrefix, "direct")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("want 3 entries, got %d", len(entries))
+	}
+	if entries[0].Message != "hello 1" || filepath.Base(entries[0].Caller.File) != "zapadapter_test.go" || entries[0].Caller.Line != line {
+		t.Errorf("first entry: %s at %s", entries[0].Message, entries[0].Caller)
+	}
+	if fields := entries[1].ContextMap(); fields[logger.CtxTraceId] != "abc" || fields["error"] != "boom" {
+		t.Errorf("context fields: %v", fields)
+	}
+	if entries[2].Level != zapcore.WarnLevel || entries[2].Caller.Line != line+4 {
+		t.Errorf("Outlog(): %v at %s", entries[2].Level, entries[2].Caller)
+	}
+}
+
+// TestCtxFromMiddleware -- идент трассировки, положенный промежуточным обработчиком http, виден адаптеру
+func TestCtxFromMiddleware(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	lgr := zapadapter.New(zap.New(core))
+	handler := loghttp.TracingMiddleware(&logger.BaseLogger{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lgr.Ctx(r.Context()).Info("handled")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(loghttp.HeaderRequestId, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].ContextMap()[logger.CtxTraceId] != "req-42" {
+		t.Errorf("entries: %v", entries)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/Arhat109/logger/pkg/adapters/zerologadapter
+
+go 1.19
+
+require (
+	github.com/Arhat109/logger v0.0.0
+	github.com/rs/zerolog v1.33.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/Arhat109/logger => ../../..
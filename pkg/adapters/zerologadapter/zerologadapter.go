@@ -0,0 +1,173 @@
+// Package zerologadapter -- github.com/rs/zerolog за интерфейсами logger.Levelable и logger.Loggable.
+// Отдельный модуль, чтобы зависимости zerolog не попадали в основной пакет.
+// Время записи zerolog ставит сам, если логгер создан с .Timestamp(), параметр now в Outlog() не применяется.
+package zerologadapter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+// Logger -- адаптер zerolog
+type Logger struct {
+	zl       zerolog.Logger
+	isCaller bool
+	traceKey string
+}
+
+// Option -- настройка адаптера
+type Option func(lgr *Logger)
+
+// WithCaller -- добавлять к записям поле zerolog.CallerFieldName с местом вызова методов адаптера
+// (вместо .Caller() в самом zerolog, который показал бы место внутри адаптера)
+func WithCaller() Option {
+	return func(lgr *Logger) { lgr.isCaller = true }
+}
+
+// WithTraceKey -- ключ трассировки в контексте для Ctx() и поля записи, по умолчанию logger.CtxTraceId
+func WithTraceKey(key string) Option {
+	return func(lgr *Logger) { lgr.traceKey = key }
+}
+
+// New -- адаптер поверх настроенного zerolog логгера
+func New(zl zerolog.Logger, opts ...Option) *Logger {
+	lgr := &Logger{zl: zl, traceKey: logger.CtxTraceId}
+	for _, opt := range opts {
+		opt(lgr)
+	}
+	return lgr
+}
+
+// Zerolog -- zerolog логгер с полями адаптера
+func (lgr *Logger) Zerolog() zerolog.Logger { return lgr.zl }
+
+// GetLevel -- действующий уровень (логгера и глобальный zerolog) в шкале logger.LogPanicLevel..LogDebugLevel
+func (lgr *Logger) GetLevel() int {
+	level := lgr.zl.GetLevel()
+	if global := zerolog.GlobalLevel(); global > level {
+		level = global
+	}
+	return FromZerologLevel(level)
+}
+
+// Outlog -- запись с заданной глубины вызовов @see logger.Loggable
+func (lgr *Logger) Outlog(depth int, _ time.Time, level, message string) {
+	lgr.output(depth+1, ToZerologLevel(logger.ToLevel(level)), message, nil)
+}
+
+func (lgr *Logger) Debug(msg string, args ...any) { lgr.log(zerolog.DebugLevel, msg, args, nil) }
+func (lgr *Logger) Info(msg string, args ...any)  { lgr.log(zerolog.InfoLevel, msg, args, nil) }
+func (lgr *Logger) Warn(msg string, args ...any)  { lgr.log(zerolog.WarnLevel, msg, args, nil) }
+func (lgr *Logger) Error(msg string, args ...any) { lgr.log(zerolog.ErrorLevel, msg, args, nil) }
+func (lgr *Logger) Fatal(msg string, args ...any) { lgr.log(zerolog.FatalLevel, msg, args, nil) }
+func (lgr *Logger) Panic(msg string, args ...any) { lgr.log(zerolog.PanicLevel, msg, args, nil) }
+
+func (lgr *Logger) WarnE(err error, msg string, args ...any) {
+	lgr.log(zerolog.WarnLevel, msg, args, err)
+}
+func (lgr *Logger) ErrorE(err error, msg string, args ...any) {
+	lgr.log(zerolog.ErrorLevel, msg, args, err)
+}
+
+// With -- адаптер с добавленными полями @see logger.BaseLogger.With()
+func (lgr *Logger) With(fields ...logger.Field) *Logger {
+	child := *lgr
+	ctx := lgr.zl.With()
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok {
+			ctx = ctx.AnErr(field.Key, err)
+			continue
+		}
+		ctx = ctx.Interface(field.Key, field.Value)
+	}
+	child.zl = ctx.Logger()
+	return &child
+}
+
+// Ctx -- адаптер с полем трассировки из контекста, если оно там есть, и самим контекстом в записи
+func (lgr *Logger) Ctx(ctx context.Context) *Logger {
+	child := *lgr
+	zctx := lgr.zl.With().Ctx(ctx)
+	if traceId := logger.TraceIdFromContext(ctx, lgr.traceKey); traceId != "" {
+		zctx = zctx.Str(lgr.traceKey, traceId)
+	}
+	child.zl = zctx.Logger()
+	return &child
+}
+
+// log -- глубина 2: над log() метод уровня, над ним -- место вызова. Сообщение форматируется, только если уровень разрешен
+func (lgr *Logger) log(level zerolog.Level, msg string, args []any, err error) {
+	if level < zerolog.FatalLevel && (level < lgr.zl.GetLevel() || level < zerolog.GlobalLevel()) {
+		return
+	}
+	lgr.output(2, level, fmt.Sprintf(msg, args...), err)
+}
+
+// output -- запись, Fatal и Panic -- как в zerolog: завершение процесса и паника
+func (lgr *Logger) output(depth int, level zerolog.Level, message string, err error) {
+	var event *zerolog.Event
+	switch level {
+	case zerolog.FatalLevel:
+		event = lgr.zl.Fatal()
+	case zerolog.PanicLevel:
+		event = lgr.zl.Panic()
+	default:
+		event = lgr.zl.WithLevel(level)
+	}
+	if event == nil { // уровень запрещен
+		return
+	}
+	if err != nil {
+		event = event.Err(err)
+	}
+	if lgr.isCaller {
+		if _, file, line, ok := runtime.Caller(depth + 1); ok {
+			event = event.Str(zerolog.CallerFieldName, filepath.Base(file)+":"+strconv.Itoa(line))
+		}
+	}
+	event.Msg(message)
+}
+
+// ToZerologLevel -- уровень logger.LogPanicLevel..LogDebugLevel уровнем zerolog
+func ToZerologLevel(level int) zerolog.Level {
+	switch {
+	case level <= logger.LogPanicLevel:
+		return zerolog.PanicLevel
+	case level <= logger.LogFatalLevel:
+		return zerolog.FatalLevel
+	case level <= logger.LogErrorLevel:
+		return zerolog.ErrorLevel
+	case level <= logger.LogWarnLevel:
+		return zerolog.WarnLevel
+	case level <= logger.LogInfoLevel:
+		return zerolog.InfoLevel
+	}
+	return zerolog.DebugLevel
+}
+
+// FromZerologLevel -- уровень zerolog в шкале logger.LogPanicLevel..LogDebugLevel, Trace -- как Debug
+func FromZerologLevel(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return logger.LogDebugLevel
+	case zerolog.InfoLevel:
+		return logger.LogInfoLevel
+	case zerolog.WarnLevel:
+		return logger.LogWarnLevel
+	case zerolog.ErrorLevel:
+		return logger.LogErrorLevel
+	case zerolog.FatalLevel:
+		return logger.LogFatalLevel
+	case zerolog.PanicLevel:
+		return logger.LogPanicLevel
+	}
+	return logger.LogNoneLevel
+}
@@ -0,0 +1,81 @@
+package zerologadapter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/Arhat109/logger/pkg/adapters/zerologadapter"
+	loghttp "github.com/Arhat109/logger/pkg/http"
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func thisLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestAdapter(t *testing.T) {
+	out := &bytes.Buffer{}
+	lgr := zerologadapter.New(zerolog.New(out).Level(zerolog.InfoLevel), zerologadapter.WithCaller())
+	var _ logger.Levelable = lgr
+	var _ logger.Loggable = lgr
+
+	if lgr.GetLevel() != logger.LogInfoLevel {
+		t.Errorf("GetLevel() = %d", lgr.GetLevel())
+	}
+
+	line := thisLine() + 1
+	lgr.Info("hello %d", 1)
+	lgr.Debug("hidden")
+	ctx := context.WithValue(context.Background(), logger.CtxTraceId, "abc")
+	lgr.Ctx(ctx).ErrorE(errors.New("boom"), "failed")
+	lgr.Outlog(0, time.Now(), logger.LogWarnPrefix, "direct")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 records, got:\n%s", out)
+	}
+	var recs [3]map[string]any
+	for i := range lines {
+		if err := json.Unmarshal([]byte(lines[i]), &recs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if recs[0]["message"] != "hello 1" || recs[0]["caller"] != "zerologadapter_test.go:"+strconv.Itoa(line) {
+		t.Errorf("first record: %v", recs[0])
+	}
+	if recs[1][logger.CtxTraceId] != "abc" || recs[1]["error"] != "boom" || recs[1]["level"] != "error" {
+		t.Errorf("context fields: %v", recs[1])
+	}
+	if recs[2]["level"] != "warn" || recs[2]["caller"] != "zerologadapter_test.go:"+strconv.Itoa(line+4) {
+		t.Errorf("Outlog(): %v", recs[2])
+	}
+}
+
+// TestCtxFromMiddleware -- идент трассировки, положенный промежуточным обработчиком http, виден адаптеру
+func TestCtxFromMiddleware(t *testing.T) {
+	out := &bytes.Buffer{}
+	lgr := zerologadapter.New(zerolog.New(out))
+	handler := loghttp.TracingMiddleware(&logger.BaseLogger{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lgr.Ctx(r.Context()).Info("handled")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(loghttp.HeaderRequestId, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var rec map[string]any
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil || rec[logger.CtxTraceId] != "req-42" {
+		t.Errorf("record %s: %v", out, err)
+	}
+}
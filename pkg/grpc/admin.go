@@ -0,0 +1,218 @@
+package grpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative adminpb/logadmin.proto
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/grpc/adminpb"
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Размер буфера записей потока TailLogs: по умолчанию и наибольший
+const (
+	TailBuffer    = 256
+	MaxTailBuffer = 4096
+)
+
+// AdminServer -- сервис LogAdmin: уровни логгеров иерархии работающего процесса, в т.ч. на время:
+// "db" в DEBUG на 5 минут, затем прежний уровень; поток записей процесса. Подключение -- RegisterAdmin(server, nil)
+type AdminServer struct {
+	adminpb.UnimplementedLogAdminServer
+	Registry *logger.Registry
+
+	mu       sync.Mutex
+	restores map[string]*levelRestore
+}
+
+// levelRestore -- прежний уровень логгера, который вернется по таймеру
+type levelRestore struct {
+	timer *time.Timer
+	level int
+	isSet bool
+	at    time.Time
+}
+
+// NewAdminServer -- сервис (в куче!) над заданной иерархией, nil -- иерархия процесса @see logger.DefaultRegistry()
+func NewAdminServer(reg *logger.Registry) *AdminServer {
+	if reg == nil {
+		reg = logger.DefaultRegistry()
+	}
+	return &AdminServer{Registry: reg, restores: map[string]*levelRestore{}}
+}
+
+// RegisterAdmin -- создает сервис и регистрирует его на сервере gRPC
+func RegisterAdmin(server grpc.ServiceRegistrar, reg *logger.Registry) *AdminServer {
+	admin := NewAdminServer(reg)
+	adminpb.RegisterLogAdminServer(server, admin)
+	return admin
+}
+
+// GetLevel -- действующий уровень существующего логгера, иначе codes.NotFound
+func (admin *AdminServer) GetLevel(_ context.Context, req *adminpb.GetLevelRequest) (*adminpb.LoggerLevel, error) {
+	info, ok := admin.Registry.Lookup(req.GetLogger())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "logger %q not found", req.GetLogger())
+	}
+	return admin.loggerLevel(info), nil
+}
+
+// SetLevel -- свой уровень логгера (создается при отсутствии) и потомков без своего уровня.
+// С ttl прежний уровень вернется по таймеру, повторная установка до его срабатывания сохраняет самый первый.
+func (admin *AdminServer) SetLevel(_ context.Context, req *adminpb.SetLevelRequest) (*adminpb.SetLevelResponse, error) {
+	level := logger.ToLevel(req.GetLevel())
+	if level == logger.LogNoneLevel {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown level %q", req.GetLevel())
+	}
+	var ttl time.Duration
+	if req.Ttl != nil {
+		if err := req.Ttl.CheckValid(); err != nil || req.Ttl.AsDuration() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "ttl must be positive, got %v", req.Ttl.AsDuration())
+		}
+		ttl = req.Ttl.AsDuration()
+	}
+
+	reg := admin.Registry
+	reg.Named(req.GetLogger())
+	previous, _ := reg.Lookup(req.GetLogger())
+	name := previous.Name
+
+	admin.mu.Lock()
+	restore := admin.restores[name]
+	if restore != nil {
+		restore.timer.Stop()
+		previous.Level, previous.IsLevelSet = restore.level, restore.isSet
+	}
+	delete(admin.restores, name)
+	reg.SetLevel(name, level)
+	if ttl > 0 {
+		restore = &levelRestore{level: previous.Level, isSet: previous.IsLevelSet, at: time.Now().Add(ttl)}
+		restore.timer = time.AfterFunc(ttl, func() { admin.restore(name, restore) })
+		admin.restores[name] = restore
+	}
+	admin.mu.Unlock()
+
+	current, _ := reg.Lookup(name)
+	reg.Root().Info("log level of %q set to %s for %v", name, levelName(level), ttl)
+	return &adminpb.SetLevelResponse{
+		Current:  admin.loggerLevel(current),
+		Previous: &adminpb.LoggerLevel{Logger: name, Level: levelName(previous.Level), LevelValue: int32(previous.Level), IsSet: previous.IsLevelSet},
+	}, nil
+}
+
+// ListLoggers -- логгеры иерархии в порядке обхода, с префиксом -- только эта часть иерархии
+func (admin *AdminServer) ListLoggers(_ context.Context, req *adminpb.ListLoggersRequest) (*adminpb.ListLoggersResponse, error) {
+	prefix := strings.Trim(req.GetPrefix(), ". ")
+	resp := &adminpb.ListLoggersResponse{}
+	// List(), а не Walk(): loggerLevel() берет свой мьютекс, а SetLevel() под ним меняет иерархию
+	for _, info := range admin.Registry.List() {
+		if prefix == "" || info.Name == prefix || strings.HasPrefix(info.Name, prefix+".") {
+			resp.Loggers = append(resp.Loggers, admin.loggerLevel(info))
+		}
+	}
+	return resp, nil
+}
+
+// TailLogs -- записи всех логгеров процесса до отмены клиентом @see logger.Tail(). Если клиент не успевает
+// забирать записи и буфер переполнен, поток завершается codes.ResourceExhausted, вывод лога не ждет.
+func (admin *AdminServer) TailLogs(req *adminpb.TailLogsRequest, stream adminpb.LogAdmin_TailLogsServer) error {
+	filter := logger.TailFilter{TraceId: req.GetTraceId()}
+	if req.GetMinLevel() != "" {
+		if filter.Level = logger.ToLevel(req.GetMinLevel()); filter.Level == logger.LogNoneLevel {
+			return status.Errorf(codes.InvalidArgument, "unknown level %q", req.GetMinLevel())
+		}
+	}
+	if req.GetMessageRegex() != "" {
+		re, err := regexp.Compile(req.GetMessageRegex())
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "bad message regex: %v", err)
+		}
+		filter.Message = re
+	}
+	size := int(req.GetBuffer())
+	if size <= 0 {
+		size = TailBuffer
+	}
+	if size > MaxTailBuffer {
+		size = MaxTailBuffer
+	}
+
+	sub := logger.Tail(size, filter)
+	defer sub.Close()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case rec, ok := <-sub.C:
+			if !ok {
+				return status.Errorf(codes.ResourceExhausted, "tail subscriber is too slow, buffer of %d records overflowed", size)
+			}
+			if err := stream.Send(logRecord(rec)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// restore -- возврат прежнего уровня, если его не отменила новая установка
+func (admin *AdminServer) restore(name string, restore *levelRestore) {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+	if admin.restores[name] != restore {
+		return
+	}
+	delete(admin.restores, name)
+	if restore.isSet {
+		admin.Registry.SetLevel(name, restore.level)
+	} else {
+		admin.Registry.ResetLevel(name)
+	}
+	admin.Registry.Root().Info("log level of %q restored to %s", name, levelName(restore.level))
+}
+
+// loggerLevel -- уровень логгера для ответа, со временем возврата прежнего
+func (admin *AdminServer) loggerLevel(info logger.LoggerInfo) *adminpb.LoggerLevel {
+	level := &adminpb.LoggerLevel{
+		Logger:     info.Name,
+		Level:      levelName(info.Level),
+		LevelValue: int32(info.Level),
+		IsSet:      info.IsLevelSet,
+	}
+	admin.mu.Lock()
+	if restore := admin.restores[info.Name]; restore != nil {
+		level.RestoreAt = timestamppb.New(restore.at)
+	}
+	admin.mu.Unlock()
+	return level
+}
+
+// levelName -- имя уровня: "debug".."panic"
+func levelName(level int) string {
+	return logger.LevelName(logger.LevelPrefix(level))
+}
+
+// logRecord -- запись лога для ответа, значения полей -- как у fmt.Sprint
+func logRecord(rec *logger.TailRecord) *adminpb.LogRecord {
+	msg := &adminpb.LogRecord{
+		Time:    timestamppb.New(rec.Time),
+		Level:   logger.LevelName(rec.Prefix),
+		Logger:  rec.Logger,
+		Message: rec.Message,
+		TraceId: rec.TraceId,
+		Fields:  make(map[string]string, len(rec.Fields)),
+		Line:    strings.TrimSuffix(string(rec.Line), "\n"),
+	}
+	for _, field := range rec.Fields {
+		msg.Fields[field.Key] = fmt.Sprint(field.Value)
+	}
+	return msg
+}
@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: adminpb/logadmin.proto
+
+// Управление уровнями логгеров работающего процесса @see pkg/grpc/admin.go
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	LogAdmin_GetLevel_FullMethodName    = "/logger.admin.v1.LogAdmin/GetLevel"
+	LogAdmin_SetLevel_FullMethodName    = "/logger.admin.v1.LogAdmin/SetLevel"
+	LogAdmin_ListLoggers_FullMethodName = "/logger.admin.v1.LogAdmin/ListLoggers"
+	LogAdmin_TailLogs_FullMethodName    = "/logger.admin.v1.LogAdmin/TailLogs"
+)
+
+// LogAdminClient is the client API for LogAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LogAdminClient interface {
+	// GetLevel -- действующий уровень логгера
+	GetLevel(ctx context.Context, in *GetLevelRequest, opts ...grpc.CallOption) (*LoggerLevel, error)
+	// SetLevel -- свой уровень логгера и его потомков, с ttl -- на время, затем прежний
+	SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error)
+	// ListLoggers -- логгеры иерархии и их уровни
+	ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error)
+	// TailLogs -- записи всех логгеров процесса по мере вывода, с отбором. Не успевающий клиент отключается
+	TailLogs(ctx context.Context, in *TailLogsRequest, opts ...grpc.CallOption) (LogAdmin_TailLogsClient, error)
+}
+
+type logAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLogAdminClient(cc grpc.ClientConnInterface) LogAdminClient {
+	return &logAdminClient{cc}
+}
+
+func (c *logAdminClient) GetLevel(ctx context.Context, in *GetLevelRequest, opts ...grpc.CallOption) (*LoggerLevel, error) {
+	out := new(LoggerLevel)
+	err := c.cc.Invoke(ctx, LogAdmin_GetLevel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logAdminClient) SetLevel(ctx context.Context, in *SetLevelRequest, opts ...grpc.CallOption) (*SetLevelResponse, error) {
+	out := new(SetLevelResponse)
+	err := c.cc.Invoke(ctx, LogAdmin_SetLevel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logAdminClient) ListLoggers(ctx context.Context, in *ListLoggersRequest, opts ...grpc.CallOption) (*ListLoggersResponse, error) {
+	out := new(ListLoggersResponse)
+	err := c.cc.Invoke(ctx, LogAdmin_ListLoggers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logAdminClient) TailLogs(ctx context.Context, in *TailLogsRequest, opts ...grpc.CallOption) (LogAdmin_TailLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LogAdmin_ServiceDesc.Streams[0], LogAdmin_TailLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logAdminTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LogAdmin_TailLogsClient interface {
+	Recv() (*LogRecord, error)
+	grpc.ClientStream
+}
+
+type logAdminTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *logAdminTailLogsClient) Recv() (*LogRecord, error) {
+	m := new(LogRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogAdminServer is the server API for LogAdmin service.
+// All implementations must embed UnimplementedLogAdminServer
+// for forward compatibility
+type LogAdminServer interface {
+	// GetLevel -- действующий уровень логгера
+	GetLevel(context.Context, *GetLevelRequest) (*LoggerLevel, error)
+	// SetLevel -- свой уровень логгера и его потомков, с ttl -- на время, затем прежний
+	SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error)
+	// ListLoggers -- логгеры иерархии и их уровни
+	ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error)
+	// TailLogs -- записи всех логгеров процесса по мере вывода, с отбором. Не успевающий клиент отключается
+	TailLogs(*TailLogsRequest, LogAdmin_TailLogsServer) error
+	mustEmbedUnimplementedLogAdminServer()
+}
+
+// UnimplementedLogAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedLogAdminServer struct {
+}
+
+func (UnimplementedLogAdminServer) GetLevel(context.Context, *GetLevelRequest) (*LoggerLevel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLevel not implemented")
+}
+func (UnimplementedLogAdminServer) SetLevel(context.Context, *SetLevelRequest) (*SetLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLevel not implemented")
+}
+func (UnimplementedLogAdminServer) ListLoggers(context.Context, *ListLoggersRequest) (*ListLoggersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoggers not implemented")
+}
+func (UnimplementedLogAdminServer) TailLogs(*TailLogsRequest, LogAdmin_TailLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method TailLogs not implemented")
+}
+func (UnimplementedLogAdminServer) mustEmbedUnimplementedLogAdminServer() {}
+
+// UnsafeLogAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LogAdminServer will
+// result in compilation errors.
+type UnsafeLogAdminServer interface {
+	mustEmbedUnimplementedLogAdminServer()
+}
+
+func RegisterLogAdminServer(s grpc.ServiceRegistrar, srv LogAdminServer) {
+	s.RegisterService(&LogAdmin_ServiceDesc, srv)
+}
+
+func _LogAdmin_GetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).GetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogAdmin_GetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).GetLevel(ctx, req.(*GetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogAdmin_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogAdmin_SetLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).SetLevel(ctx, req.(*SetLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogAdmin_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoggersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogAdminServer).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogAdmin_ListLoggers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogAdminServer).ListLoggers(ctx, req.(*ListLoggersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogAdmin_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogAdminServer).TailLogs(m, &logAdminTailLogsServer{stream})
+}
+
+type LogAdmin_TailLogsServer interface {
+	Send(*LogRecord) error
+	grpc.ServerStream
+}
+
+type logAdminTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *logAdminTailLogsServer) Send(m *LogRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// LogAdmin_ServiceDesc is the grpc.ServiceDesc for LogAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LogAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logger.admin.v1.LogAdmin",
+	HandlerType: (*LogAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevel",
+			Handler:    _LogAdmin_GetLevel_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LogAdmin_SetLevel_Handler,
+		},
+		{
+			MethodName: "ListLoggers",
+			Handler:    _LogAdmin_ListLoggers_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailLogs",
+			Handler:       _LogAdmin_TailLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "adminpb/logadmin.proto",
+}
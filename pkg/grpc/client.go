@@ -0,0 +1,199 @@
+package grpc
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRetryAttempt -- номер попытки вызова, его ставят перехватчики повторов. Встроенные повторы gRPC
+// передают число прошлых попыток в MetadataPreviousAttempts.
+const (
+	MetadataRetryAttempt     = "x-retry-attempt"
+	MetadataPreviousAttempts = "grpc-previous-rpc-attempts"
+)
+
+// Ключи полей записи о вызове
+const (
+	MethodKey   = "method"
+	TargetKey   = "target"
+	CodeKey     = "code"
+	DurationKey = "duration"
+	AttemptKey  = "attempt"
+)
+
+// SetOutgoingTraceId -- кладет идент трассировки в исходящие метаданные (x-trace-id и traceparent).
+// Идент берется из исходящих метаданных, затем из контекста по ключу key, иначе создается новый
+// и кладется в контекст, чтобы и свои записи вызывающего были с ним.
+func SetOutgoingTraceId(ctx context.Context, key string) (context.Context, string) {
+	if key == "" {
+		key = logger.CtxTraceId
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if values := md.Get(MetadataTraceId); len(values) > 0 && values[0] != "" {
+			return ctx, values[0]
+		}
+	}
+	traceId := logger.TraceIdFromContext(ctx, key)
+	if traceId == "" {
+		traceId = uuid.NewString()
+		ctx = logger.ContextWithTraceId(ctx, key, traceId)
+	}
+	pairs := []string{MetadataTraceId, traceId}
+	if traceparent := Traceparent(traceId); traceparent != "" {
+		pairs = append(pairs, MetadataTraceparent, traceparent)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...), traceId
+}
+
+// Traceparent -- заголовок W3C для идента трассировки из 32 hex цифр или uuid, прочим -- ""
+// Родительский идент -- случайный, флаг sampled установлен.
+func Traceparent(traceId string) string {
+	if len(traceId) == 36 {
+		traceId = strings.ReplaceAll(traceId, "-", "")
+	}
+	if len(traceId) != 32 {
+		return ""
+	}
+	traceId = strings.ToLower(traceId)
+	if _, err := hex.DecodeString(traceId); err != nil {
+		return ""
+	}
+	parent := uuid.New()
+	return "00-" + traceId + "-" + hex.EncodeToString(parent[:8]) + "-01"
+}
+
+// CallAttempt -- номер попытки вызова из исходящих метаданных, 0 -- неизвестен
+func CallAttempt(ctx context.Context) int {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return 0
+	}
+	if values := md.Get(MetadataRetryAttempt); len(values) > 0 {
+		if attempt, err := strconv.Atoi(values[len(values)-1]); err == nil {
+			return attempt
+		}
+	}
+	if values := md.Get(MetadataPreviousAttempts); len(values) > 0 {
+		if previous, err := strconv.Atoi(values[len(values)-1]); err == nil {
+			return previous + 1
+		}
+	}
+	return 0
+}
+
+// CodeLevel -- уровень записи по коду статуса: ошибки клиента и временные сбои -- WARN,
+// ошибки сервера -- ERROR, успех -- INFO
+func CodeLevel(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return logger.LogInfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange,
+		codes.DeadlineExceeded, codes.ResourceExhausted, codes.Unavailable:
+		return logger.LogWarnLevel
+	default:
+		return logger.LogErrorLevel
+	}
+}
+
+// UnaryClientInterceptor -- передает идент трассировки серверу и пишет запись о каждом вызове:
+// метод, адрес сервера, код статуса, время и номер попытки. Уровень по коду @see CodeLevel(), WithCodeLevel()
+func UnaryClientInterceptor(lgr *logger.BaseLogger, opts ...LogOption) grpc.UnaryClientInterceptor {
+	options := newLogOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx, traceId := SetOutgoingTraceId(ctx, lgr.GetTraceKey())
+		if !options.isLogged(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		startedAt := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		md, _ := metadata.FromOutgoingContext(ctx)
+		options.logCall(lgr, ctx, &callInfo{
+			message: "grpc client call", method: method, target: cc.Target(), startedAt: startedAt, err: err,
+			req: req, resp: reply, md: md, attempt: CallAttempt(ctx), traceId: traceId,
+		})
+		return err
+	}
+}
+
+// StreamClientInterceptor -- то же для потоков: запись по завершении потока (io.EOF -- успех), по ответу потока
+// клиента без потока ответов (CloseAndRecv), по отмене контекста вызова или при ошибке открытия. Запись одна.
+func StreamClientInterceptor(lgr *logger.BaseLogger, opts ...LogOption) grpc.StreamClientInterceptor {
+	options := newLogOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, traceId := SetOutgoingTraceId(ctx, lgr.GetTraceKey())
+		if !options.isLogged(method) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		call := &callInfo{
+			message: "grpc client stream", method: method, target: cc.Target(), startedAt: time.Now(),
+			md: md, attempt: CallAttempt(ctx), traceId: traceId,
+		}
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			call.err = err
+			options.logCall(lgr, ctx, call)
+			return nil, err
+		}
+		wrapped := &clientStream{ClientStream: stream, isUnaryReply: !desc.ServerStreams, done: make(chan struct{}),
+			finish: func(err error) {
+				call.err = err
+				options.logCall(lgr, ctx, call)
+			}}
+		if ctx.Done() != nil {
+			go wrapped.watch(ctx)
+		}
+		return wrapped, nil
+	}
+}
+
+// clientStream -- поток клиента, сообщающий о своем завершении один раз
+type clientStream struct {
+	grpc.ClientStream
+	// isUnaryReply -- ответ один (поток только от клиента): поток завершен первым же RecvMsg()
+	isUnaryReply bool
+	once         sync.Once
+	done         chan struct{}
+	finish       func(err error)
+}
+
+func (stream *clientStream) RecvMsg(msg any) error {
+	err := stream.ClientStream.RecvMsg(msg)
+	switch {
+	case err == io.EOF:
+		stream.end(nil)
+	case err != nil || stream.isUnaryReply:
+		stream.end(err)
+	}
+	return err
+}
+
+// end -- запись о завершении, только первая из всех причин
+func (stream *clientStream) end(err error) {
+	stream.once.Do(func() {
+		close(stream.done)
+		stream.finish(err)
+	})
+}
+
+// watch -- запись о брошенном или отмененном вызывающим потоке по отмене контекста
+func (stream *clientStream) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		stream.end(status.FromContextError(ctx.Err()).Err())
+	case <-stream.done:
+	}
+}
@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	logger.RegisterErrorFields(StatusFields)
+}
+
+// StatusFields -- код и сообщение gRPC статуса ошибки для записи лога @see logger.Err()
+// Статус ищется по всей цепочке причин, а не только у самой ошибки, как в status.FromError()
+func StatusFields(err error) []logger.Field {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return nil
+	}
+	st := grpcErr.GRPCStatus()
+	return []logger.Field{
+		{Key: "grpc_code", Value: st.Code().String()},
+		{Key: "grpc_message", Value: st.Message()},
+	}
+}
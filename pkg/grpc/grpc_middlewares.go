@@ -2,64 +2,122 @@ package grpc
 
 import (
 	"context"
+	"time"
+
 	"github.com/Arhat109/logger/pkg/logger"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"time"
+	"google.golang.org/grpc/metadata"
 )
 
-// QueryAndSetTracingId -- проверяет наличие уникального ид запроса в контексте, и устанавливает при отсутствии
-func QueryAndSetTracingId(ctx context.Context, name string) {
-	tag := ctx.Value(name)
-	if tag == nil {
-		if u, err := uuid.NewRandom(); err == nil {
-			ctx = context.WithValue(ctx, name, u.String())
+// Ключи метаданных трассировки: свой x-trace-id и W3C traceparent
+const (
+	MetadataTraceId     = "x-trace-id"
+	MetadataTraceparent = "traceparent"
+)
+
+// QueryAndSetTracingId -- ищет уникальный идент запроса в контексте, затем во входящих метаданных
+// (x-trace-id, traceparent), создает новый при отсутствии. Возвращает контекст с идентом под ключом key.
+func QueryAndSetTracingId(ctx context.Context, key string) (context.Context, string) {
+	if key == "" {
+		key = logger.CtxTraceId
+	}
+	if traceId := logger.TraceIdFromContext(ctx, key); traceId != "" {
+		return ctx, traceId
+	}
+	traceId := IncomingTraceId(ctx)
+	if traceId == "" {
+		traceId = uuid.NewString()
+	}
+	return logger.ContextWithTraceId(ctx, key, traceId), traceId
+}
+
+// IncomingTraceId -- идент трассировки из входящих метаданных или "", если его там нет
+func IncomingTraceId(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(MetadataTraceId); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	if values := md.Get(MetadataTraceparent); len(values) > 0 {
+		if traceId, ok := logger.ParseTraceparent(values[0]); ok {
+			return traceId
 		}
 	}
+	return ""
 }
 
-// UnaryTracingInterceptor -- отслеживает наличие уникального идента запроса и создает его в случае отсутствия
-func UnaryTracingInterceptor(lgr logger.Loggable) grpc.UnaryServerInterceptor {
+// UnaryTracingInterceptor -- отслеживает наличие уникального идента запроса и создает его в случае отсутствия.
+// Обработчик получает контекст с идентом @see logger.TraceIdFromContext(), клиент -- заголовок x-trace-id.
+func UnaryTracingInterceptor(lgr *logger.BaseLogger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		QueryAndSetTracingId(ctx, lgr.GetTraceId())
+		ctx, traceId := QueryAndSetTracingId(ctx, lgr.GetTraceKey())
+		if err := grpc.SetHeader(ctx, metadata.Pairs(MetadataTraceId, traceId)); err != nil {
+			logger.Diagnose(err)
+		}
 		return handler(ctx, req)
 	}
 }
 
-// UnaryLoggerInterceptor returns a new unary server interceptors that adds zap.Logger to the context.
-func UnaryLoggerInterceptor(lgr logger.Loggable) grpc.UnaryServerInterceptor {
+// UnaryLoggerInterceptor -- запись о вызове: метод, код статуса, время выполнения, идент трассировки из контекста
+// и что задано настройками @see LogOption. Уровень по коду статуса @see CodeLevel(), WithCodeLevel()
+func UnaryLoggerInterceptor(lgr *logger.BaseLogger, opts ...LogOption) grpc.UnaryServerInterceptor {
+	options := newLogOptions(opts)
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !options.isLogged(info.FullMethod) {
+			return handler(ctx, req)
+		}
 		startedAt := time.Now()
 		resp, err := handler(ctx, req)
-		executed := time.Since(startedAt)
-		if err != nil {
-			lgr.ErrorCtx(ctx, "%s, executedTime=%d usec.", err.Error(), executed.Microseconds())
-		} else {
-			lgr.InfoCtx(ctx, "UnaryLoggerInterceptor(): success from %s, executedTime=%d usec.", info.FullMethod, executed.Microseconds())
-		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		options.logCall(lgr, ctx, &callInfo{
+			message: "grpc call", method: info.FullMethod, startedAt: startedAt, err: err, req: req, resp: resp, md: md,
+		})
 		return resp, err
 	}
 }
 
-// StreamTracingInterceptor -- отслеживает наличие уникального идента запроса и создает его в случае отсутствия
-func StreamTracingInterceptor(lgr logger.Loggable) grpc.StreamServerInterceptor {
+// StreamTracingInterceptor -- отслеживает наличие уникального идента запроса и создает его в случае отсутствия.
+// Контекст потока подменяется оберткой, т.к. у grpc.ServerStream его не заменить.
+func StreamTracingInterceptor(lgr *logger.BaseLogger) grpc.StreamServerInterceptor {
 	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		QueryAndSetTracingId(stream.Context(), lgr.GetTraceId())
-		return handler(srv, stream)
+		ctx, traceId := QueryAndSetTracingId(stream.Context(), lgr.GetTraceKey())
+		if err := stream.SetHeader(metadata.Pairs(MetadataTraceId, traceId)); err != nil {
+			logger.Diagnose(err)
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
 	}
 }
 
-// StreamLoggerInterceptor returns a new unary server interceptors that adds zap.Logger to the context.
-func StreamLoggerInterceptor(lgr logger.Loggable) grpc.StreamServerInterceptor {
+// StreamLoggerInterceptor -- запись о завершении потока, как у UnaryLoggerInterceptor(), с итогами: число
+// и размер сообщений в обе стороны, время до первого сообщения. WithMessages() -- еще и запись о каждом сообщении.
+func StreamLoggerInterceptor(lgr *logger.BaseLogger, opts ...LogOption) grpc.StreamServerInterceptor {
+	options := newLogOptions(opts)
 	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		startedAt := time.Now()
-		err := handler(srv, stream)
-		executed := time.Since(startedAt)
-		if err != nil {
-			lgr.ErrorCtx(stream.Context(), ", executedTime=%d usec.", err.Error(), executed.Microseconds())
-		} else {
-			lgr.InfoCtx(stream.Context(), "StreamLoggerInterceptor(): success from %s, executedTime=%d usec.", info.FullMethod, executed.Microseconds())
+		if !options.isLogged(info.FullMethod) {
+			return handler(srv, stream)
 		}
+		counting := &countingStream{
+			ServerStream: stream, lgr: lgr, method: info.FullMethod, startedAt: time.Now(), messages: options.messages,
+		}
+		err := handler(srv, counting)
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		options.logCall(lgr, stream.Context(), &callInfo{
+			message: "grpc stream", method: info.FullMethod, startedAt: counting.startedAt, err: err, md: md,
+			extra: counting.totals(),
+		})
 		return err
 	}
 }
+
+// contextStream -- поток сервера со своим контекстом
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *contextStream) Context() context.Context {
+	return stream.ctx
+}
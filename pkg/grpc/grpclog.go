@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc/grpclog"
+)
+
+// GrpcVerbosityEnv -- переменная окружения порога подробности, как у стандартного логгера gRPC
+const GrpcVerbosityEnv = "GRPC_GO_LOG_VERBOSITY_LEVEL"
+
+// GrpcLogger -- внутренние сообщения gRPC в наш логгер: Info -> INFO, Warning -> WARN, Error -> ERROR,
+// Fatal -> FATAL и завершение процесса. Подключается так: grpclog.SetLoggerV2(grpc.NewGrpcLogger(lgr))
+type GrpcLogger struct {
+	Lgr *logger.BaseLogger
+	// Verbosity -- порог подробности для V(l): gRPC пишет подробности, если V(l) == true, т.е. l <= Verbosity
+	Verbosity int
+}
+
+var _ grpclog.LoggerV2 = (*GrpcLogger)(nil)
+var _ grpclog.DepthLoggerV2 = (*GrpcLogger)(nil)
+
+// NewGrpcLogger -- адаптер (в куче!) с порогом подробности из GRPC_GO_LOG_VERBOSITY_LEVEL (0 по умолчанию)
+func NewGrpcLogger(lgr *logger.BaseLogger) *GrpcLogger {
+	return &GrpcLogger{Lgr: lgr, Verbosity: logger.ToInt(logger.LookupEnv(GrpcVerbosityEnv, 0))}
+}
+
+// Info .. Fatalf -- методы grpclog.LoggerV2, аргументы -- как у fmt.Print, fmt.Println и fmt.Printf.
+// Вызываются через grpclog.Info() и т.п., место вызова -- вызвавший их код gRPC.
+
+func (gl *GrpcLogger) Info(args ...any) {
+	gl.out(2, logger.LogInfoLevel, fmt.Sprint(args...))
+}
+func (gl *GrpcLogger) Infoln(args ...any) {
+	gl.out(2, logger.LogInfoLevel, sprintln(args))
+}
+func (gl *GrpcLogger) Infof(format string, args ...any) {
+	gl.out(2, logger.LogInfoLevel, fmt.Sprintf(format, args...))
+}
+func (gl *GrpcLogger) Warning(args ...any) {
+	gl.out(2, logger.LogWarnLevel, fmt.Sprint(args...))
+}
+func (gl *GrpcLogger) Warningln(args ...any) {
+	gl.out(2, logger.LogWarnLevel, sprintln(args))
+}
+func (gl *GrpcLogger) Warningf(format string, args ...any) {
+	gl.out(2, logger.LogWarnLevel, fmt.Sprintf(format, args...))
+}
+func (gl *GrpcLogger) Error(args ...any) {
+	gl.out(2, logger.LogErrorLevel, fmt.Sprint(args...))
+}
+func (gl *GrpcLogger) Errorln(args ...any) {
+	gl.out(2, logger.LogErrorLevel, sprintln(args))
+}
+func (gl *GrpcLogger) Errorf(format string, args ...any) {
+	gl.out(2, logger.LogErrorLevel, fmt.Sprintf(format, args...))
+}
+func (gl *GrpcLogger) Fatal(args ...any) {
+	gl.fatal(2, fmt.Sprint(args...))
+}
+func (gl *GrpcLogger) Fatalln(args ...any) {
+	gl.fatal(2, sprintln(args))
+}
+func (gl *GrpcLogger) Fatalf(format string, args ...any) {
+	gl.fatal(2, fmt.Sprintf(format, args...))
+}
+
+// V -- писать ли подробности уровня l
+func (gl *GrpcLogger) V(l int) bool {
+	return l <= gl.Verbosity
+}
+
+// InfoDepth .. FatalDepth -- для компонентов gRPC (grpclog.Component): depth считается от вызвавшего
+// внутренний grpclog.InfoDepth(), место вызова -- код gRPC, а не обертки компонента.
+// Аргументы -- как у fmt.Println
+
+func (gl *GrpcLogger) InfoDepth(depth int, args ...any) {
+	gl.out(depth+2, logger.LogInfoLevel, sprintln(args))
+}
+func (gl *GrpcLogger) WarningDepth(depth int, args ...any) {
+	gl.out(depth+2, logger.LogWarnLevel, sprintln(args))
+}
+func (gl *GrpcLogger) ErrorDepth(depth int, args ...any) {
+	gl.out(depth+2, logger.LogErrorLevel, sprintln(args))
+}
+func (gl *GrpcLogger) FatalDepth(depth int, args ...any) {
+	gl.fatal(depth+2, sprintln(args))
+}
+
+// out -- вывод с глубины depth от метода адаптера: 1 -- вызвавший метод, 2 -- вызвавший его и т.д.
+func (gl *GrpcLogger) out(depth, level int, message string) {
+	if gl.Lgr.GetLevel() >= level {
+		gl.Lgr.Outlog(depth+1, time.Now(), logger.LevelPrefix(level), message)
+	}
+}
+
+// fatal -- запись FATAL независимо от уровня логгера, завершители и выход: gRPC ждет, что Fatal не вернется
+func (gl *GrpcLogger) fatal(depth int, message string) {
+	gl.Lgr.Outlog(depth+1, time.Now(), logger.LogFatalPrefix, message)
+	gl.Lgr.RunExitHooks()
+	os.Exit(1)
+}
+
+// sprintln -- как fmt.Sprintln, без завершающего перевода строки
+func sprintln(args []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
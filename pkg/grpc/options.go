@@ -0,0 +1,266 @@
+package grpc
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Ключи необязательных полей записи о вызове @see LogOption
+const (
+	PeerKey        = "peer"
+	DeadlineKey    = "deadline"
+	RequestKey     = "request"
+	ResponseKey    = "response"
+	MetadataPrefix = "md."
+)
+
+// RedactedValue -- чем заменяются скрываемые строковые поля сообщений, прочие -- очищаются @see WithRedact()
+const RedactedValue = "[REDACTED]"
+
+// LogOption -- настройка записей перехватчиков о вызовах
+type LogOption func(opts *logOptions)
+
+type logOptions struct {
+	codeLevels map[codes.Code]int
+	include    []string
+	exclude    []string
+	peer       bool
+	deadline   bool
+	metadata   []string
+	payloads   bool
+	maxPayload int
+	redact     map[string]bool
+	messages   bool
+}
+
+// WithCodeLevel -- свой уровень записи для кода статуса, например codes.NotFound -> logger.LogInfoLevel.
+// Прочие коды -- по CodeLevel()
+func WithCodeLevel(code codes.Code, level int) LogOption {
+	return func(opts *logOptions) { opts.codeLevels[code] = level }
+}
+
+// WithCodeLevels -- то же сразу для нескольких кодов
+func WithCodeLevels(levels map[codes.Code]int) LogOption {
+	return func(opts *logOptions) {
+		for code, level := range levels {
+			opts.codeLevels[code] = level
+		}
+	}
+}
+
+// WithMethods -- писать только вызовы методов по шаблонам path.Match: "/pkg.Service/*", "pkg.Service/Get*"
+func WithMethods(globs ...string) LogOption {
+	return func(opts *logOptions) { opts.include = append(opts.include, globs...) }
+}
+
+// WithoutMethods -- не писать вызовы этих методов, например "/grpc.health.v1.Health/*"
+func WithoutMethods(globs ...string) LogOption {
+	return func(opts *logOptions) { opts.exclude = append(opts.exclude, globs...) }
+}
+
+// WithPeer -- адрес клиента в записи сервера
+func WithPeer() LogOption {
+	return func(opts *logOptions) { opts.peer = true }
+}
+
+// WithDeadline -- сколько времени оставалось до крайнего срока вызова на его начало
+func WithDeadline() LogOption {
+	return func(opts *logOptions) { opts.deadline = true }
+}
+
+// WithMetadata -- значения этих ключей метаданных полями "md.<ключ>", прочие метаданные не пишутся
+func WithMetadata(keys ...string) LogOption {
+	return func(opts *logOptions) {
+		for _, key := range keys {
+			opts.metadata = append(opts.metadata, strings.ToLower(key))
+		}
+	}
+}
+
+// WithPayloads -- запрос и ответ в protojson, длиннее maxSize байт -- обрезаются (0 -- без ограничения)
+func WithPayloads(maxSize int) LogOption {
+	return func(opts *logOptions) {
+		opts.payloads = true
+		opts.maxPayload = maxSize
+	}
+}
+
+// WithRedact -- скрывать поля сообщений с этими именами (как в proto или json) на любой вложенности
+func WithRedact(fields ...string) LogOption {
+	return func(opts *logOptions) {
+		for _, field := range fields {
+			opts.redact[field] = true
+		}
+	}
+}
+
+func newLogOptions(opts []LogOption) *logOptions {
+	options := &logOptions{codeLevels: map[codes.Code]int{}, redact: map[string]bool{}}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// isLogged -- писать ли вызов метода по шаблонам WithMethods/WithoutMethods
+func (opts *logOptions) isLogged(method string) bool {
+	if len(opts.include) > 0 && !matchMethod(opts.include, method) {
+		return false
+	}
+	return !matchMethod(opts.exclude, method)
+}
+
+// codeLevel -- уровень записи для кода статуса
+func (opts *logOptions) codeLevel(code codes.Code) int {
+	if level, ok := opts.codeLevels[code]; ok {
+		return level
+	}
+	return CodeLevel(code)
+}
+
+// callInfo -- что известно о завершенном вызове
+type callInfo struct {
+	message   string
+	method    string
+	target    string
+	startedAt time.Time
+	err       error
+	req, resp any
+	md        metadata.MD
+	attempt   int
+	traceId   string
+	// extra -- свои поля вида вызова, например итоги потока
+	extra []logger.Field
+}
+
+// logCall -- запись о вызове по настройкам. Идент трассировки -- из call или из контекста
+func (opts *logOptions) logCall(lgr *logger.BaseLogger, ctx context.Context, call *callInfo) {
+	code := status.Code(call.err)
+	level := opts.codeLevel(code)
+	if lgr.GetLevel() < level {
+		return
+	}
+
+	fields := make([]logger.Field, 0, 10+len(opts.metadata)+len(call.extra))
+	fields = append(fields, logger.Field{Key: MethodKey, Value: call.method})
+	if call.target != "" {
+		fields = append(fields, logger.Field{Key: TargetKey, Value: call.target})
+	}
+	fields = append(fields,
+		logger.Field{Key: CodeKey, Value: code.String()},
+		logger.Field{Key: DurationKey, Value: time.Since(call.startedAt)},
+	)
+	if call.attempt > 0 {
+		fields = append(fields, logger.Field{Key: AttemptKey, Value: call.attempt})
+	}
+	if opts.peer {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			fields = append(fields, logger.Field{Key: PeerKey, Value: p.Addr.String()})
+		}
+	}
+	if opts.deadline {
+		if deadline, ok := ctx.Deadline(); ok {
+			fields = append(fields, logger.Field{Key: DeadlineKey, Value: deadline.Sub(call.startedAt)})
+		}
+	}
+	for _, key := range opts.metadata {
+		if values := call.md.Get(key); len(values) > 0 {
+			fields = append(fields, logger.Field{Key: MetadataPrefix + key, Value: strings.Join(values, ",")})
+		}
+	}
+	fields = append(fields, call.extra...)
+	if opts.payloads {
+		if payload, ok := opts.payload(call.req); ok {
+			fields = append(fields, logger.Field{Key: RequestKey, Value: payload})
+		}
+		if payload, ok := opts.payload(call.resp); ok && call.err == nil {
+			fields = append(fields, logger.Field{Key: ResponseKey, Value: payload})
+		}
+	}
+	if call.err != nil {
+		fields = append(fields, logger.Err(call.err))
+	}
+	if call.traceId == "" {
+		call.traceId = lgr.TraceId(ctx)
+	}
+	if call.traceId != "" {
+		fields = append(fields, logger.Field{Key: lgr.GetTraceKey(), Value: call.traceId})
+	}
+	lgr.OutlogFields(1, call.startedAt, logger.LevelPrefix(level), call.message, fields)
+}
+
+// payload -- сообщение в protojson со скрытыми полями и ограничением длины, не proto -- не пишется
+func (opts *logOptions) payload(msg any) (string, bool) {
+	pm, ok := msg.(proto.Message)
+	if !ok || pm == nil || !pm.ProtoReflect().IsValid() {
+		return "", false
+	}
+	if len(opts.redact) > 0 {
+		pm = proto.Clone(pm)
+		redactMessage(pm.ProtoReflect(), opts.redact)
+	}
+	data, err := protojson.Marshal(pm)
+	if err != nil {
+		logger.Diagnose(err)
+		return "", false
+	}
+	if opts.maxPayload > 0 {
+		return logger.TruncateMessage(string(data), opts.maxPayload), true
+	}
+	return string(data), true
+}
+
+// redactMessage -- скрывает поля с заданными именами в сообщении и во всех вложенных
+func redactMessage(msg protoreflect.Message, names map[string]bool) {
+	var hidden []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case names[string(fd.Name())] || names[fd.JSONName()]:
+			hidden = append(hidden, fd)
+		case fd.IsList() && fd.Message() != nil:
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message(), names)
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			value.Map().Range(func(_ protoreflect.MapKey, item protoreflect.Value) bool {
+				redactMessage(item.Message(), names)
+				return true
+			})
+		case fd.Message() != nil && !fd.IsMap():
+			redactMessage(value.Message(), names)
+		}
+		return true
+	})
+	for _, fd := range hidden {
+		if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+			msg.Set(fd, protoreflect.ValueOfString(RedactedValue))
+		} else {
+			msg.Clear(fd)
+		}
+	}
+}
+
+// matchMethod -- полное имя метода "/pkg.Service/Method" подходит под один из шаблонов, начальный "/" не обязателен
+func matchMethod(globs []string, method string) bool {
+	for _, glob := range globs {
+		if !strings.HasPrefix(glob, "/") {
+			glob = "/" + glob
+		}
+		if ok, _ := path.Match(glob, method); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryHandler -- ошибка, которую получит клиент вместо паники обработчика метода.
+// Ошибка без статуса gRPC дойдет до клиента как codes.Unknown
+type RecoveryHandler func(ctx context.Context, method string, value any) error
+
+// RecoveryOption -- настройка перехватчиков паник
+type RecoveryOption func(opts *recoveryOptions)
+
+type recoveryOptions struct {
+	handler RecoveryHandler
+	level   int
+}
+
+// WithRecoveryHandler -- свой выбор статуса ответа по значению паники
+func WithRecoveryHandler(handler RecoveryHandler) RecoveryOption {
+	return func(opts *recoveryOptions) { opts.handler = handler }
+}
+
+// WithRecoveryLevel -- уровень записи о панике, по умолчанию logger.LogPanicLevel
+func WithRecoveryLevel(level int) RecoveryOption {
+	return func(opts *recoveryOptions) { opts.level = level }
+}
+
+// InternalError -- ответ по умолчанию: codes.Internal без подробностей паники, они -- только в логе
+func InternalError(_ context.Context, _ string, _ any) error {
+	return status.Error(codes.Internal, "internal error")
+}
+
+// UnaryRecoveryInterceptor -- перехват паники в обработчике: запись со значением, типом и стеком паники,
+// методом и идентом трассировки, клиенту -- ошибка codes.Internal (или своя @see WithRecoveryHandler()).
+// Ставится последним в цепочке, ближе всех к обработчику, чтобы прочие перехватчики видели ошибку.
+func UnaryRecoveryInterceptor(lgr *logger.BaseLogger, opts ...RecoveryOption) grpc.UnaryServerInterceptor {
+	options := newRecoveryOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				resp, err = nil, options.recovered(lgr, ctx, info.FullMethod, value)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor -- то же для потоков
+func StreamRecoveryInterceptor(lgr *logger.BaseLogger, opts ...RecoveryOption) grpc.StreamServerInterceptor {
+	options := newRecoveryOptions(opts)
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				err = options.recovered(lgr, stream.Context(), info.FullMethod, value)
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+func newRecoveryOptions(opts []RecoveryOption) *recoveryOptions {
+	options := &recoveryOptions{handler: InternalError, level: logger.LogPanicLevel}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// recovered -- запись о панике и ответ клиенту. Вызывается из отложенной функции, стек -- с места паники
+func (opts *recoveryOptions) recovered(lgr *logger.BaseLogger, ctx context.Context, method string, value any) error {
+	if lgr.GetLevel() >= opts.level {
+		valueField := logger.Field{Key: logger.PanicKey, Value: value}
+		if err, ok := value.(error); ok {
+			valueField = logger.Err(err)
+		}
+		fields := []logger.Field{
+			{Key: MethodKey, Value: method},
+			valueField,
+			{Key: logger.PanicTypeKey, Value: fmt.Sprintf("%T", value)},
+		}
+		if field, ok := lgr.TraceField(ctx); ok {
+			fields = append(fields, field)
+		}
+		lgr.OutlogStack(2, time.Now(), logger.LevelPrefix(opts.level), "grpc handler panic", fields)
+	}
+	return opts.handler(ctx, method, value)
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// Ключи полей итогов потока и записей о сообщениях
+const (
+	SentKey          = "msgs_sent"
+	ReceivedKey      = "msgs_received"
+	BytesSentKey     = "bytes_sent"
+	BytesReceivedKey = "bytes_received"
+	FirstSentKey     = "first_sent"
+	FirstReceivedKey = "first_received"
+	DirectionKey     = "direction"
+	SeqKey           = "seq"
+	SizeKey          = "size"
+)
+
+// WithMessages -- запись уровня DEBUG о каждом сообщении потока: направление, номер, размер
+func WithMessages() LogOption {
+	return func(opts *logOptions) { opts.messages = true }
+}
+
+// messageStats -- счетчики одного направления потока. Отправка и прием могут идти из разных горутин
+type messageStats struct {
+	count atomic.Int64
+	bytes atomic.Int64
+	first atomic.Int64 // время до первого сообщения от начала потока, 0 -- сообщений не было
+}
+
+// countingStream -- поток сервера, считающий сообщения и их размеры в обе стороны
+type countingStream struct {
+	grpc.ServerStream
+	lgr       *logger.BaseLogger
+	method    string
+	startedAt time.Time
+	messages  bool
+	sent      messageStats
+	received  messageStats
+}
+
+func (stream *countingStream) SendMsg(msg any) error {
+	err := stream.ServerStream.SendMsg(msg)
+	if err == nil {
+		stream.account(&stream.sent, "sent", msg)
+	}
+	return err
+}
+
+func (stream *countingStream) RecvMsg(msg any) error {
+	err := stream.ServerStream.RecvMsg(msg)
+	if err == nil {
+		stream.account(&stream.received, "received", msg)
+	}
+	return err
+}
+
+// account -- учет сообщения и, по настройке, запись о нем
+func (stream *countingStream) account(stats *messageStats, direction string, msg any) {
+	now := time.Now()
+	size := messageSize(msg)
+	seq := stats.count.Add(1)
+	stats.bytes.Add(int64(size))
+	if seq == 1 {
+		first := now.Sub(stream.startedAt)
+		if first <= 0 {
+			first = 1
+		}
+		stats.first.Store(int64(first))
+	}
+
+	if !stream.messages || stream.lgr.GetLevel() < logger.LogDebugLevel {
+		return
+	}
+	fields := make([]logger.Field, 0, 5)
+	fields = append(fields,
+		logger.Field{Key: MethodKey, Value: stream.method},
+		logger.Field{Key: DirectionKey, Value: direction},
+		logger.Field{Key: SeqKey, Value: seq},
+		logger.Field{Key: SizeKey, Value: size},
+	)
+	if field, ok := stream.lgr.TraceField(stream.Context()); ok {
+		fields = append(fields, field)
+	}
+	stream.lgr.OutlogFields(2, now, logger.LogDebugPrefix, "grpc stream message", fields)
+}
+
+// totals -- итоги потока для его завершающей записи
+func (stream *countingStream) totals() []logger.Field {
+	fields := make([]logger.Field, 0, 6)
+	fields = append(fields,
+		logger.Field{Key: SentKey, Value: stream.sent.count.Load()},
+		logger.Field{Key: ReceivedKey, Value: stream.received.count.Load()},
+		logger.Field{Key: BytesSentKey, Value: stream.sent.bytes.Load()},
+		logger.Field{Key: BytesReceivedKey, Value: stream.received.bytes.Load()},
+	)
+	if first := stream.sent.first.Load(); first != 0 {
+		fields = append(fields, logger.Field{Key: FirstSentKey, Value: time.Duration(first)})
+	}
+	if first := stream.received.first.Load(); first != 0 {
+		fields = append(fields, logger.Field{Key: FirstReceivedKey, Value: time.Duration(first)})
+	}
+	return fields
+}
+
+// messageSize -- размер сообщения в protobuf, не proto -- 0
+func messageSize(msg any) int {
+	if pm, ok := msg.(proto.Message); ok {
+		return proto.Size(pm)
+	}
+	return 0
+}
@@ -0,0 +1,299 @@
+package http
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Заголовки трассировки: W3C Trace Context и привычный X-Request-Id
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderRequestId   = "X-Request-Id"
+)
+
+// AccessStructured, AccessCombined -- вид записи журнала доступа, можно оба сразу @see WithAccessFormat()
+const (
+	AccessStructured = 1 << iota // запись логгера с полями method, path, status, bytes, duration, peer
+	AccessCombined               // строка Combined Log Format как у Apache/nginx, выводится в Out без обрамления
+)
+
+// Ключи полей записи журнала доступа
+const (
+	MethodKey    = "method"
+	PathKey      = "path"
+	StatusKey    = "status"
+	BytesKey     = "bytes"
+	DurationKey  = "duration"
+	PeerKey      = "peer"
+	UserAgentKey = "user_agent"
+)
+
+// maxRequestIdLen -- длиннее пришедший X-Request-Id не принимается, генерируется свой
+const maxRequestIdLen = 128
+
+// Option -- настройка промежуточного обработчика @see Middleware()
+type Option func(opts *options)
+
+type options struct {
+	format  int
+	header  string
+	message string
+}
+
+// WithAccessFormat -- AccessStructured (по умолчанию), AccessCombined или AccessStructured|AccessCombined
+func WithAccessFormat(format int) Option {
+	return func(opts *options) { opts.format = format }
+}
+
+// WithResponseHeader -- заголовок ответа с идентом трассировки, по умолчанию X-Request-Id. "" -- не отдавать
+func WithResponseHeader(name string) Option {
+	return func(opts *options) { opts.header = name }
+}
+
+// WithAccessMessage -- свой текст структурной записи вместо "http request"
+func WithAccessMessage(message string) Option {
+	return func(opts *options) { opts.message = message }
+}
+
+// Middleware -- трассировка и журнал доступа одним обработчиком: TracingMiddleware(AccessLogMiddleware(next))
+func Middleware(lgr *logger.BaseLogger, opts ...Option) func(http.Handler) http.Handler {
+	tracing := TracingMiddleware(lgr, opts...)
+	access := AccessLogMiddleware(lgr, opts...)
+	return func(next http.Handler) http.Handler {
+		return tracing(access(next))
+	}
+}
+
+// TracingMiddleware -- берет идент трассировки из traceparent или X-Request-Id, создает при отсутствии,
+// кладет в контекст запроса под ключом логгера @see logger.ContextWithTraceId() и отдает в заголовке ответа.
+func TracingMiddleware(lgr *logger.BaseLogger, opts ...Option) func(http.Handler) http.Handler {
+	options := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceId := lgr.TraceId(r.Context())
+			if traceId == "" {
+				traceId = RequestTraceId(r)
+				r = r.WithContext(lgr.ContextWithTrace(r.Context(), traceId))
+			}
+			if options.header != "" {
+				w.Header().Set(options.header, traceId)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AccessLogMiddleware -- запись о каждом запросе после его обработки: метод, путь, статус, размер ответа,
+// время и адрес клиента. Уровень по статусу: 5xx -- ERROR, 4xx -- WARN, прочие -- INFO.
+func AccessLogMiddleware(lgr *logger.BaseLogger, opts ...Option) func(http.Handler) http.Handler {
+	options := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startedAt := time.Now()
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+			if rw.status == 0 {
+				rw.status = http.StatusOK
+			}
+
+			if options.format&AccessStructured != 0 {
+				level, prefix := statusLevel(rw.status)
+				if lgr.GetLevel() >= level {
+					fields := []logger.Field{
+						{Key: MethodKey, Value: r.Method},
+						{Key: PathKey, Value: r.URL.Path},
+						{Key: StatusKey, Value: rw.status},
+						{Key: BytesKey, Value: rw.bytes},
+						{Key: DurationKey, Value: time.Since(startedAt)},
+						{Key: PeerKey, Value: r.RemoteAddr},
+					}
+					if ua := r.UserAgent(); ua != "" {
+						fields = append(fields, logger.Field{Key: UserAgentKey, Value: ua})
+					}
+					if field, ok := lgr.TraceField(r.Context()); ok {
+						fields = append(fields, field)
+					}
+					lgr.OutlogFields(0, startedAt, prefix, options.message, fields)
+				}
+			}
+			if options.format&AccessCombined != 0 {
+				buf := make([]byte, 0, 256)
+				FormatCombined(&buf, r, startedAt, rw.status, rw.bytes)
+				_ = lgr.OutMessage(&buf)
+			}
+		})
+	}
+}
+
+// RequestTraceId -- идент трассировки запроса: trace-id из traceparent, иначе X-Request-Id, иначе новый uuid
+func RequestTraceId(r *http.Request) string {
+	if traceId, ok := logger.ParseTraceparent(r.Header.Get(HeaderTraceparent)); ok {
+		return traceId
+	}
+	if requestId := r.Header.Get(HeaderRequestId); isValidRequestId(requestId) {
+		return requestId
+	}
+	return uuid.NewString()
+}
+
+// FormatCombined -- добавляет в буфер строку Combined Log Format:
+// host ident authuser [02/Jan/2006:15:04:05 -0700] "GET /path HTTP/1.1" status bytes "referer" "user-agent"
+func FormatCombined(buf *[]byte, r *http.Request, now time.Time, status, bytes int) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	user := "-"
+	if r.URL.User != nil && r.URL.User.Username() != "" {
+		user = r.URL.User.Username()
+	} else if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	}
+
+	appendDash(buf, host)
+	*buf = append(*buf, " - "...)
+	appendDash(buf, user)
+	*buf = append(*buf, " ["...)
+	*buf = now.AppendFormat(*buf, "02/Jan/2006:15:04:05 -0700")
+	*buf = append(*buf, "] \""...)
+	appendQuoted(buf, r.Method)
+	*buf = append(*buf, ' ')
+	appendQuoted(buf, r.URL.RequestURI())
+	*buf = append(*buf, ' ')
+	appendQuoted(buf, r.Proto)
+	*buf = append(*buf, "\" "...)
+	*buf = strconv.AppendInt(*buf, int64(status), 10)
+	*buf = append(*buf, ' ')
+	if bytes > 0 {
+		*buf = strconv.AppendInt(*buf, int64(bytes), 10)
+	} else {
+		*buf = append(*buf, '-')
+	}
+	*buf = append(*buf, " \""...)
+	appendDash(buf, r.Referer())
+	*buf = append(*buf, "\" \""...)
+	appendDash(buf, r.UserAgent())
+	*buf = append(*buf, '"', '\n')
+}
+
+// responseWriter -- запоминает статус и число записанных байт ответа
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(data)
+	rw.bytes += n
+	return n, err
+}
+
+// Flush -- для потоковых ответов (SSE и т.п.), если исходный это умеет
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack -- для websocket и прочих смен протокола, если исходный это умеет. Статус в записи -- 101
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err == nil && rw.status == 0 {
+		rw.status = http.StatusSwitchingProtocols
+	}
+	return conn, brw, err
+}
+
+// Push -- HTTP/2 server push, если исходный это умеет
+func (rw *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := rw.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap -- исходный ResponseWriter для http.NewResponseController() с Go 1.20, до него -- методы выше
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func newOptions(opts []Option) options {
+	options := options{format: AccessStructured, header: HeaderRequestId, message: "http request"}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// statusLevel -- уровень и префикс записи по статусу ответа
+func statusLevel(status int) (int, string) {
+	switch {
+	case status >= 500:
+		return logger.LogErrorLevel, logger.LogErrorPrefix
+	case status >= 400:
+		return logger.LogWarnLevel, logger.LogWarnPrefix
+	default:
+		return logger.LogInfoLevel, logger.LogInfoPrefix
+	}
+}
+
+// isValidRequestId -- чужой идент принимается только разумной длины и из видимых ASCII символов
+func isValidRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' || id[i] == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// appendDash -- значение или "-", если оно пустое
+func appendDash(buf *[]byte, str string) {
+	if str == "" {
+		*buf = append(*buf, '-')
+		return
+	}
+	appendQuoted(buf, str)
+}
+
+// appendQuoted -- значение внутри кавычек CLF: кавычки, обратная косая и управляющие символы -- как \xHH
+func appendQuoted(buf *[]byte, str string) {
+	const hexDigits = "0123456789abcdef"
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == '"' || c == '\\':
+			*buf = append(*buf, '\\', c)
+		case c < ' ' || c == 0x7f:
+			*buf = append(*buf, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			*buf = append(*buf, c)
+		}
+	}
+}
+
+// compile-time: запись журнала не ломает интерфейсы ответа
+var _ http.Flusher = (*responseWriter)(nil)
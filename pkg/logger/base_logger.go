@@ -1,11 +1,11 @@
 package logger
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,60 +18,181 @@ import (
 // 5. Закрытие файла вывода при логировании Fatal() и Panic()
 // блокировка мьютексом только непосредственно вывода сообщения в поток(файл)
 // Все поля структуры публичны, для полноценного внедрения по мере потребности программиста в развитии пакета.
+// Кроме изменяемых на работающем логгере (уровень, флаги, стек, тема, поля): они меняются атомарно
+// через SetLevel() и т.п., Reload() и Registry, чтобы запись из других горутин шла без блокировок.
+// Поля формата (Format, ToJson, Layout, TraceKey, TimeFormat, Location, RawText, MaxMessageLen) задаются до начала
+// записи. Логгеры узлов Registry берут их у корня иерархии, свои у них не действуют.
 type BaseLogger struct {
 	Mu sync.Mutex
-	// куда выводить сообщения (nil - в никуда)
+	// куда выводить сообщения (nil - в никуда), замена на ходу -- под Mu
 	Out io.Writer
-	// Level -- наибольший разрешенный уровень вывода сообщений
-	Level int
-	// Flags -- Что выводить в лог
-	Flags int
-	// ToJson -- маршаллер сообщений в json, если задан. Иначе - строка
+	// ToJson -- маршаллер сообщений в json, если задан. Иначе - по Format
 	ToJson LogJsonHandler
+	// Format -- формат вывода записи LogFormatText|LogFormatLogfmt, если не задан ToJson
+	Format int
+	// Layout -- скомпилированный шаблон текстовой строки, если задан. Иначе фиксированный формат FormatString()
+	Layout []LogAppender
+	// Name -- имя логгера в иерархии "db.pool", выводится по флагу LogPrefix @see Named()
+	Name string
+	// TraceKey -- ключ поля (и контекста) сквозной трассировки
+	TraceKey string
+	// TimeFormat -- формат времени @see FormatTimestamp(), "" -- по флагам FormatTime()
+	TimeFormat string
+	// Location -- часовой пояс времени записей, nil -- местное или UTC по флагу LogUTC
+	Location *time.Location
+
+	// RawText -- выводить текст сообщений как есть, без экранирования управляющих символов @see FormatEscaped()
+	RawText bool
+	// MaxMessageLen -- наибольшая длина сообщения в байтах, длиннее -- обрезается с пометкой @see TruncateMessage()
+	// 0 -- без ограничения
+	MaxMessageLen int
+	// ExitHooks -- завершители, выполняются перед завершением процесса по Fatal() и в Recover() @see OnExit()
+	ExitHooks []func()
+
+	// live -- изменяемые на ходу настройки, заменяются целиком @see settings(), reconfigure()
+	live atomic.Pointer[liveSettings]
+	// envPrefix -- префикс переменных окружения из настроек, для перечитывания @see WatchConfigFile()
+	envPrefix string
+	// files -- открытые логгером файлы выводов, закрываются при замене выводов @see Reload()
+	files []io.Closer
+	// root -- логгер, чей вывод (и мьютекс) используется порожденными через With(), меняется иерархией на ходу
+	root atomic.Pointer[BaseLogger]
+	// onChange -- оповещение иерархии, корнем которой стал логгер, об изменении его настроек @see Registry
+	onChange atomic.Pointer[func()]
 }
 
-const bufSize = 1024
+// liveSettings -- настройки, изменяемые на работающем логгере. Не меняются после публикации: изменение --
+// новой копией @see reconfigure(). Запись берет их один раз и выводится целиком по одним настройкам.
+type liveSettings struct {
+	// level -- наибольший разрешенный уровень вывода сообщений
+	level int
+	// flags -- что выводить в лог
+	flags int
+	// stackLevel -- к записям этого уровня и важнее добавляется стек вызовов, LogNoneLevel -- без стека
+	stackLevel int
+	// theme -- тема раскраски по флагу LogLevelColored, nil -- тема по умолчанию @see ParseTheme()
+	theme *ColorTheme
+	// fields -- поля, добавляемые к каждой записи @see With()
+	fields []Field
+	// format -- логгер, чьи поля формата действуют для этого: корень у логгеров узлов иерархии, nil -- свои
+	format *BaseLogger
+}
 
-var bufPool = sync.Pool{
-	New: func() any { return new([bufSize]byte) },
+// noSettings -- настройки логгера до Init(): все нулевые
+var noSettings liveSettings
+
+// settings -- действующие изменяемые настройки
+func (baselog *BaseLogger) settings() *liveSettings {
+	if live := baselog.live.Load(); live != nil {
+		return live
+	}
+	return &noSettings
 }
 
-func (baselog *BaseLogger) GetLevel() int { return baselog.Level }
+// reconfigure -- замена изменяемых настроек их копией, исправленной fn, с оповещением иерархии @see Registry
+func (baselog *BaseLogger) reconfigure(fn func(live *liveSettings)) {
+	baselog.swapSettings(fn)
+	if onChange := baselog.onChange.Load(); onChange != nil {
+		(*onChange)()
+	}
+}
+
+// swapSettings -- замена изменяемых настроек без оповещения. Одновременные замены не теряются.
+func (baselog *BaseLogger) swapSettings(fn func(live *liveSettings)) {
+	for {
+		old := baselog.live.Load()
+		live := noSettings
+		if old != nil {
+			live = *old
+		}
+		fn(&live)
+		if baselog.live.CompareAndSwap(old, &live) {
+			return
+		}
+	}
+}
+
+// GetLevel -- наибольший разрешенный уровень вывода сообщений
+func (baselog *BaseLogger) GetLevel() int { return baselog.settings().level }
+
+// SetLevel -- изменить наибольший разрешенный уровень, в т.ч. на работающем логгере
+func (baselog *BaseLogger) SetLevel(level int) {
+	baselog.reconfigure(func(live *liveSettings) { live.level = level })
+}
+
+// GetFlags -- что выводится в лог @see LogDate..
+func (baselog *BaseLogger) GetFlags() int { return baselog.settings().flags }
+
+// SetFlags -- изменить выводимое в лог, в т.ч. на работающем логгере
+func (baselog *BaseLogger) SetFlags(flags int) {
+	baselog.reconfigure(func(live *liveSettings) { live.flags = flags })
+}
+
+// GetStackLevel -- с какого уровня к записям добавляется стек вызовов, LogNoneLevel -- без стека
+func (baselog *BaseLogger) GetStackLevel() int { return baselog.settings().stackLevel }
+
+// SetStackLevel -- изменить уровень записей со стеком вызовов, в т.ч. на работающем логгере
+func (baselog *BaseLogger) SetStackLevel(level int) {
+	baselog.reconfigure(func(live *liveSettings) { live.stackLevel = level })
+}
+
+// SetTheme -- изменить тему раскраски по флагу LogLevelColored, nil -- тема по умолчанию
+func (baselog *BaseLogger) SetTheme(theme *ColorTheme) {
+	baselog.reconfigure(func(live *liveSettings) { live.theme = theme })
+}
+
+// GetFields -- поля, добавляемые к каждой записи. Не изменять: общие с записями в других горутинах!
+func (baselog *BaseLogger) GetFields() []Field { return baselog.settings().fields }
+
+// formatter -- логгер, чьи поля формата действуют: корень иерархии для логгеров узлов, иначе сам
+func (baselog *BaseLogger) formatter() *BaseLogger { return baselog.settings().formatOf(baselog) }
+
+func (live *liveSettings) formatOf(baselog *BaseLogger) *BaseLogger {
+	if live.format != nil {
+		return live.format
+	}
+	return baselog
+}
 
 // Init -- настройка логгера из структуры настроек @see ./config, возвращает себя (this)
 // param Args -- доп. параметры конфигуратора (если надо!): тут можно задать маршаллер в json
 // Ошибка открытия файла лога возвращается в параметре, исключительно для улучшения работы escape алгоритма.
 func (baselog *BaseLogger) Init(cfg *LogConfig, retErr *error, args ...any) *BaseLogger {
-	baselog.Level = cfg.Level
-	baselog.Flags = cfg.Flags
+	baselog.MaxMessageLen = cfg.MaxMessageLen
+	baselog.RawText = cfg.RawText
 	baselog.Mu = sync.Mutex{}
-
-	switch cfg.Out {
-	case "devnul":
-		baselog.Out = nil
-	case "stdout":
-		baselog.Out = os.Stdout
-	case "stderr":
-		baselog.Out = os.Stderr
-	default:
-		if cfg.Out != "" {
-			var file *os.File
-			var err error
-
-			if _, err = os.Stat(cfg.Out); errors.Is(err, os.ErrNotExist) {
-				file, err = os.OpenFile(cfg.Out, os.O_RDWR|os.O_CREATE, 0664)
-			} else {
-				file, err = os.OpenFile(cfg.Out, os.O_RDWR|os.O_APPEND, 0664)
-			}
-			if err != nil {
-				*retErr = fmt.Errorf("BaseLogger.Init() OpenFile has: %s", err.Error())
-			}
-			baselog.Out = file
+	baselog.TraceKey = cfg.TraceId
+	baselog.envPrefix = cfg.EnvPrefix
+	baselog.TimeFormat = TimeLayout(cfg.TimeFormat)
+	baselog.Location = nil
+	if cfg.TimeZone != "" {
+		loc, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			*retErr = fmt.Errorf("BaseLogger.Init() LoadLocation has: %s", err.Error())
 		}
+		baselog.Location = loc
 	}
 
-	if cfg.IsJson {
-		baselog.ToJson = BaseLogMarshal
+	out, files, err := openSinks(cfg, baselog.Out)
+	if err != nil {
+		*retErr = fmt.Errorf("BaseLogger.Init() has: %s", err.Error())
+	}
+	baselog.Out = out
+	baselog.files = files
+
+	flags, theme, err := colorSetup(cfg, baselog.Out)
+	if err != nil {
+		*retErr = fmt.Errorf("BaseLogger.Init() has: %s", err.Error())
+	}
+	baselog.live.Store(&liveSettings{level: cfg.Level, flags: flags, stackLevel: cfg.StackLevel, theme: theme})
+
+	baselog.Format = LogFormatText
+	if cfg.Format == DefLoggerLogfmt {
+		baselog.Format = LogFormatLogfmt
+	}
+	baselog.ToJson = nil
+	if cfg.IsJson || cfg.Format == DefLoggerJson {
+		baselog.Format = LogFormatJson
 		if len(args) > BaselogJsonHandlerId {
 			toJson, ok := args[BaselogJsonHandlerId].(LogJsonHandler)
 			if ok && toJson != nil {
@@ -79,115 +200,401 @@ func (baselog *BaseLogger) Init(cfg *LogConfig, retErr *error, args ...any) *Bas
 			}
 		}
 	}
-	if baselog.Level >= LogWarnLevel {
-		baselog.Info("Logger installed for with %d Level", baselog.Level)
+	if cfg.Layout != "" {
+		layout, err := CompileLayout(cfg.Layout)
+		if err != nil {
+			*retErr = fmt.Errorf("BaseLogger.Init() has: %s", err.Error())
+		}
+		baselog.Layout = layout
+	}
+	if cfg.Level >= LogWarnLevel {
+		baselog.Info("Logger installed for with %d Level", cfg.Level)
 	}
 	return baselog
 }
 
-// FormatString -- строчный режим вывода. Форматирует строку в заданном буфере
+// colorSetup -- флаги и тема: раскраска только там, где ее покажут -- в терминал или по LOG_COLOR=always
+func colorSetup(cfg *LogConfig, out io.Writer) (int, *ColorTheme, error) {
+	if cfg.Flags&LogLevelColored == 0 {
+		return cfg.Flags, nil, nil
+	}
+	if !IsColored(cfg.Color, out) {
+		return cfg.Flags &^ LogLevelColored, nil, nil
+	}
+	theme, err := ParseTheme(cfg.Theme)
+	return cfg.Flags, theme, err
+}
+
+// TimeIn -- время записи в часовом поясе логгера: Location, если задан, или UTC по флагу LogUTC
+func (baselog *BaseLogger) TimeIn(now time.Time) time.Time {
+	live := baselog.settings()
+	if location := live.formatOf(baselog).Location; location != nil {
+		return now.In(location)
+	}
+	if live.flags&LogUTC != 0 {
+		return now.UTC()
+	}
+	return now
+}
+
+// FormatString -- строчный режим вывода. Форматирует строку в заданном буфере @see FormatText()
+func (baselog *BaseLogger) FormatString(buf *[]byte, depth int, now time.Time, level, message string) {
+	rec := LogRecord{Depth: depth + 1, Now: now, Level: level, Message: message}
+	baselog.FormatText(buf, &rec)
+}
+
+// FormatText -- строчный режим вывода записи. Форматирует строку в заданном буфере
 // порядок элементов в строке фиксирован:
-// Level:{ yyyy-mm-dd hh:mm:ss.msec}{ file_name#line) | func_name#line }message\n
+// Level:{ yyyy-mm-dd hh:mm:ss.micro | TimeFormat}{ file_name#line) | func_name#line }{ [name]} message{ key=value..}\n{стек}
 // {} -- опционально, если они есть. Склеиваются перед сообщением "как есть", разделять самостоятельно!
-func (baselog *BaseLogger) FormatString(buf *[]byte, depth int, now time.Time, level, message string) {
+func (baselog *BaseLogger) FormatText(buf *[]byte, rec *LogRecord) {
 	*buf = append(*buf, "\n"...)
+	live := baselog.settings()
+	timeFormat := live.formatOf(baselog).TimeFormat
+	theme := live.colors()
 
-	if color, ok := GlDefColors[level]; baselog.Flags&LogLevelColored != 0 && ok {
-		FormatColored(buf, color, level)
-		*buf = append(*buf, ':')
+	if color := theme.LevelColor(rec.Level); color != "" {
+		FormatColored(buf, color, rec.Level)
 	} else {
-		*buf = append(*buf, level...)
-		*buf = append(*buf, ':')
+		*buf = append(*buf, rec.Level...)
 	}
+	*buf = append(*buf, ':')
 
-	if baselog.Flags&(LogDate|LogTime|LogMicroSeconds) != 0 {
-		FormatTime(buf, now, baselog.Flags)
+	if timeFormat != "" {
+		*buf = append(*buf, ' ')
+		color := formatColorStart(buf, theme.TimeColor())
+		FormatTimestamp(buf, rec.Now, timeFormat)
+		formatColorEnd(buf, color)
+	} else if live.flags&(LogDate|LogTime|LogMicroSeconds) != 0 {
+		color := formatColorStart(buf, theme.TimeColor())
+		FormatTime(buf, rec.Now, live.flags)
+		formatColorEnd(buf, color)
 	}
 
-	if baselog.Flags&(LogShortFile|LogLongFile) != 0 {
-		FormatFileLine(buf, depth+1, baselog.Flags&LogShortFile != 0)
-	} else if baselog.Flags&LogFuncName != 0 {
-		FormatFuncLine(buf, depth+1)
+	if live.flags&(LogShortFile|LogLongFile) != 0 {
+		color := formatColorStart(buf, theme.CallerColor())
+		FormatFileLine(buf, rec.Depth+2, live.flags&LogShortFile != 0)
+		formatColorEnd(buf, color)
+	} else if live.flags&LogFuncName != 0 {
+		color := formatColorStart(buf, theme.CallerColor())
+		FormatFuncLine(buf, rec.Depth+3)
+		formatColorEnd(buf, color)
+	}
+
+	if baselog.Name != "" && live.flags&LogPrefix != 0 {
+		*buf = append(*buf, " ["...)
+		*buf = append(*buf, baselog.Name...)
+		*buf = append(*buf, ']')
 	}
 
 	*buf = append(*buf, ' ')
-	*buf = append(*buf, message...)
-	if len(message) == 0 || message[len(message)-1] != '\n' {
-		*buf = append(*buf, '\n')
+	baselog.formatMessage(buf, rec.Message)
+	keyColor := theme.KeyColor()
+	for _, field := range live.fields {
+		FormatColoredField(buf, keyColor, field.Key, field.Value)
+	}
+	for _, field := range rec.Fields {
+		FormatColoredField(buf, keyColor, field.Key, field.Value)
+	}
+	*buf = append(*buf, '\n')
+	if rec.Stack != nil {
+		FormatStack(buf, rec.Stack)
 	}
 }
 
+// Colors -- тема раскраски текстового вывода: nil без флага LogLevelColored, иначе своя или тема по умолчанию
+func (baselog *BaseLogger) Colors() *ColorTheme {
+	return baselog.settings().colors()
+}
+
+func (live *liveSettings) colors() *ColorTheme {
+	if live.flags&LogLevelColored == 0 {
+		return nil
+	}
+	if live.theme == nil {
+		return DefaultTheme
+	}
+	return live.theme
+}
+
+// formatMessage -- текст сообщения без завершающего перевода строки: как есть (RawText) или с экранированием
+func (baselog *BaseLogger) formatMessage(buf *[]byte, message string) {
+	if len(message) > 0 && message[len(message)-1] == '\n' {
+		message = message[:len(message)-1]
+	}
+	if baselog.formatter().RawText {
+		*buf = append(*buf, message...)
+	} else {
+		FormatEscaped(buf, message)
+	}
+}
+
+// FormatLogfmt -- вывод в формате logfmt, одна запись -- одна строка:
+// level=info ts=yyyy-mm-ddThh:mm:ss.micro+hh:mm caller=file.go:12 msg="message"\n
+// ts в формате TimeFormat, если задан. ts и caller -- только если заданы соответствующие флаги. Значения экранируются по необходимости.
+// Стек вызовов, если есть, -- одним значением stacktrace="..." с экранированными переводами строк.
+func (baselog *BaseLogger) FormatLogfmt(buf *[]byte, rec *LogRecord) {
+	*buf = append(*buf, "level="...)
+	*buf = append(*buf, LevelName(rec.Level)...)
+	live := baselog.settings()
+
+	if timeFormat := live.formatOf(baselog).TimeFormat; timeFormat != "" {
+		*buf = append(*buf, " ts="...)
+		start := len(*buf)
+		FormatTimestamp(buf, rec.Now, timeFormat)
+		quoteLogfmtTail(buf, start)
+	} else if live.flags&(LogDate|LogTime|LogMicroSeconds) != 0 {
+		*buf = append(*buf, " ts="...)
+		FormatTimeISO(buf, rec.Now, live.flags)
+	}
+
+	if live.flags&(LogShortFile|LogLongFile) != 0 {
+		*buf = append(*buf, " caller="...)
+		FormatCaller(buf, rec.Depth+1, live.flags&LogShortFile != 0)
+	} else if live.flags&LogFuncName != 0 {
+		*buf = append(*buf, " func="...)
+		FormatFuncLine(buf, rec.Depth+3)
+	}
+
+	if baselog.Name != "" && live.flags&LogPrefix != 0 {
+		*buf = append(*buf, " "+NameKey+"="...)
+		FormatLogfmtValue(buf, baselog.Name)
+	}
+
+	*buf = append(*buf, " msg="...)
+	FormatLogfmtValue(buf, rec.Message)
+	for _, field := range live.fields {
+		FormatLogfmtField(buf, field.Key, field.Value)
+	}
+	for _, field := range rec.Fields {
+		FormatLogfmtField(buf, field.Key, field.Value)
+	}
+	if rec.Stack != nil {
+		*buf = append(*buf, " stacktrace="...)
+		FormatLogfmtStack(buf, rec.Stack)
+	}
+	*buf = append(*buf, '\n')
+}
+
+// FormatJson -- вывод записи строкой json без reflect, поля логгера -- в конце записи:
+// {"Level":"INFO ","date_time":"...","func_name":"...","file_name":"...","line_num":12,"message":"...","key":value}\n
+// Время в формате TimeFormat (unix* -- числом) или по умолчанию "2006-01-02 15:04:05.000"
+// Стек вызовов, если есть, -- "stacktrace":[{"func":"...","file":"...","line":12},..]
+func (baselog *BaseLogger) FormatJson(buf *[]byte, rec *LogRecord) {
+	live := baselog.settings()
+	timeFormat := live.formatOf(baselog).TimeFormat
+	if timeFormat == "" {
+		timeFormat = DefJsonTimeFormat
+	}
+	formatJson(buf, rec.Depth+1, rec.Now, rec.Level, rec.Message, timeFormat)
+	if baselog.Name != "" && live.flags&LogPrefix != 0 {
+		*buf = append(*buf, `,"`+NameKey+`":`...)
+		FormatJsonString(buf, baselog.Name)
+	}
+	for _, field := range live.fields {
+		*buf = append(*buf, ',')
+		FormatJsonString(buf, field.Key)
+		*buf = append(*buf, ':')
+		FormatJsonAny(buf, field.Value)
+	}
+	for _, field := range rec.Fields {
+		*buf = append(*buf, ',')
+		FormatJsonString(buf, field.Key)
+		*buf = append(*buf, ':')
+		FormatJsonAny(buf, field.Value)
+	}
+	if rec.Stack != nil {
+		*buf = append(*buf, `,"stacktrace":`...)
+		FormatJsonStack(buf, rec.Stack)
+	}
+	*buf = append(*buf, '}', '\n')
+}
+
 // OutMessage -- вывод сообщения в поток логирования(файл) или в никуда
+// Порожденные через With() логгеры выводят через исходный, под его мьютексом.
 func (baselog *BaseLogger) OutMessage(content *[]byte) error {
-	if baselog.Out != nil {
-		baselog.Mu.Lock()
-		_, err := baselog.Out.Write(*content)
+	if root := baselog.root.Load(); root != nil {
+		return root.OutMessage(content)
+	}
+	var err error
+	baselog.Mu.Lock()
+	// Registry могла передать вывод владельцу после проверки выше -- тогда пишем через него
+	if root := baselog.root.Load(); root != nil {
 		baselog.Mu.Unlock()
-		return err
+		return root.OutMessage(content)
+	}
+	if baselog.Out != nil {
+		_, err = baselog.Out.Write(*content)
+	}
+	baselog.Mu.Unlock()
+	return err
+}
+
+// owner -- логгер, владеющий выводом и мьютексом: исходный для порожденных через With(), иначе сам
+func (baselog *BaseLogger) owner() *BaseLogger {
+	if root := baselog.root.Load(); root != nil {
+		return root
+	}
+	return baselog
+}
+
+// outFallback -- запись, которую не удалось вывести, -- в stderr, если вывод не он сам
+func (baselog *BaseLogger) outFallback(content []byte) {
+	owner := baselog.owner()
+	owner.Mu.Lock()
+	out := owner.Out
+	owner.Mu.Unlock()
+	if out == os.Stderr {
+		return
+	}
+	if _, err := os.Stderr.Write(content); err != nil {
+		panic(err.Error())
 	}
-	return nil
 }
 
 // Outlog -- собственно форматилка лога и его вывод куда сказано.
 // Ориентировочно: level=6 символов, date=11, time=9, micro=4, long/short file=32/16, message <120> итого ~182символа
 // Аллоцируем тут, для обеспечения реентерабельности в горутинах.
 func (baselog *BaseLogger) Outlog(depth int, now time.Time, level, message string) {
-	abuf := bufPool.Get().(*[bufSize]byte)
-	buf := (*abuf)[:0]
+	baselog.OutlogFields(depth+1, now, level, message, nil)
+}
+
+// OutlogFields -- то же, что Outlog() с дополнительными полями этой записи (после полей логгера)
+func (baselog *BaseLogger) OutlogFields(depth int, now time.Time, level, message string, fields []Field) {
+	baselog.outlog(depth+1, now, level, message, fields, false)
+}
+
+// OutlogStack -- то же, что OutlogFields() со стеком вызовов независимо от StackLevel, например для паник
+func (baselog *BaseLogger) OutlogStack(depth int, now time.Time, level, message string, fields []Field) {
+	baselog.outlog(depth+1, now, level, message, fields, true)
+}
+
+// outlog -- вывод записи, isStack -- со стеком вызовов независимо от StackLevel
+func (baselog *BaseLogger) outlog(depth int, now time.Time, level, message string, fields []Field, isStack bool) {
 	depth++
-	if baselog.ToJson != nil { // JSON! Все формируем тут по частям:
-		if err := baselog.ToJson(&buf, depth, now, level, message); err != nil {
+	live := baselog.settings()
+	format := live.formatOf(baselog)
+	if format.MaxMessageLen > 0 && len(message) > format.MaxMessageLen {
+		message = TruncateMessage(message, format.MaxMessageLen)
+	}
+	entry := getEntry(len(message))
+	now = baselog.TimeIn(now)
+	entry.rec = LogRecord{Depth: depth, Now: now, Level: level, Message: message, Fields: fields}
+	if isStack || live.stackLevel > LogNoneLevel && ToLevel(level) <= live.stackLevel {
+		// кадры самого логгера отбрасываются при выводе @see FormatStack()
+		entry.rec.Stack = GetCallerPCs(1, entry.stack[:])
+	}
+
+	switch {
+	case format.ToJson != nil: // JSON! Все формируем тут по частям:
+		if err := format.ToJson(&entry.buf, depth, now, level, message); err != nil {
 			// преобразование в json не получилось, игнор ошибки т.к. далее не JSON:
-			baselog.FormatString(&buf, depth, now, level, message)
+			entry.buf = entry.buf[:0]
+			baselog.FormatText(&entry.buf, &entry.rec)
 		}
-	} else {
-		baselog.FormatString(&buf, depth, now, level, message)
+	case format.Format == LogFormatJson:
+		baselog.FormatJson(&entry.buf, &entry.rec)
+	case format.Format == LogFormatLogfmt:
+		baselog.FormatLogfmt(&entry.buf, &entry.rec)
+	case len(format.Layout) > 0:
+		baselog.FormatLayout(&entry.buf, &entry.rec)
+	default:
+		baselog.FormatText(&entry.buf, &entry.rec)
 	}
 
-	if err := baselog.OutMessage(&buf); err != nil && baselog.Out != os.Stderr {
-		if _, err := os.Stderr.Write(buf); err != nil {
-			panic(err.Error())
-		}
+	if err := baselog.OutMessage(&entry.buf); err != nil {
+		baselog.outFallback(entry.buf)
 	}
-	bufPool.Put(abuf)
+	if isTailed() {
+		baselog.publishTail(&entry.rec, entry.buf)
+	}
+	putEntry(entry)
 }
 
 // Простое логирование по уровням с добавлением доп. полей по настройкам
 
 func (baselog *BaseLogger) Debug(msg string, args ...any) {
-	if baselog.Level >= LogDebugLevel {
+	if baselog.GetLevel() >= LogDebugLevel {
 		baselog.Outlog(1, time.Now(), LogDebugPrefix, fmt.Sprintf(msg, args...))
 	}
 }
 func (baselog *BaseLogger) Info(msg string, args ...any) {
-	if baselog.Level >= LogInfoLevel {
+	if baselog.GetLevel() >= LogInfoLevel {
 		baselog.Outlog(1, time.Now(), LogInfoPrefix, fmt.Sprintf(msg, args...))
 	}
 }
 func (baselog *BaseLogger) Warn(msg string, args ...any) {
-	if baselog.Level >= LogWarnLevel {
+	if baselog.GetLevel() >= LogWarnLevel {
 		baselog.Outlog(1, time.Now(), LogWarnPrefix, fmt.Sprintf(msg, args...))
 	}
 }
 func (baselog *BaseLogger) Error(msg string, args ...any) {
-	if baselog.Level >= LogErrorLevel {
+	if baselog.GetLevel() >= LogErrorLevel {
 		baselog.Outlog(1, time.Now(), LogErrorPrefix, fmt.Sprintf(msg, args...))
 	}
 }
 func (baselog *BaseLogger) Fatal(msg string, args ...any) {
-	if baselog.Level >= LogFatalLevel {
+	if baselog.GetLevel() >= LogFatalLevel {
 		baselog.Outlog(1, time.Now(), LogFatalPrefix, fmt.Sprintf(msg, args...))
+		baselog.RunExitHooks()
 		os.Exit(1)
 	}
 }
 func (baselog *BaseLogger) Panic(msg string, args ...any) {
-	if baselog.Level >= LogPanicLevel {
+	if baselog.GetLevel() >= LogPanicLevel {
 		message := fmt.Sprintf(msg, args...)
 		baselog.Outlog(1, time.Now(), LogPanicPrefix, message)
 		panic(message)
 	}
 }
 
+// Логирование ошибки по уровням: к записи добавляется поле Err(err) с цепочкой причин @see FormatLogfmtError()
+
+func (baselog *BaseLogger) WarnE(err error, msg string, args ...any) {
+	if baselog.GetLevel() >= LogWarnLevel {
+		baselog.OutlogFields(1, time.Now(), LogWarnPrefix, fmt.Sprintf(msg, args...), []Field{Err(err)})
+	}
+}
+func (baselog *BaseLogger) ErrorE(err error, msg string, args ...any) {
+	if baselog.GetLevel() >= LogErrorLevel {
+		baselog.OutlogFields(1, time.Now(), LogErrorPrefix, fmt.Sprintf(msg, args...), []Field{Err(err)})
+	}
+}
+func (baselog *BaseLogger) FatalE(err error, msg string, args ...any) {
+	if baselog.GetLevel() >= LogFatalLevel {
+		baselog.OutlogFields(1, time.Now(), LogFatalPrefix, fmt.Sprintf(msg, args...), []Field{Err(err)})
+		baselog.RunExitHooks()
+		os.Exit(1)
+	}
+}
+func (baselog *BaseLogger) PanicE(err error, msg string, args ...any) {
+	if baselog.GetLevel() >= LogPanicLevel {
+		message := fmt.Sprintf(msg, args...)
+		baselog.OutlogFields(1, time.Now(), LogPanicPrefix, message, []Field{Err(err)})
+		panic(fmt.Errorf("%s: %w", message, err))
+	}
+}
+
+// With -- новый логгер (в куче!) с теми же настройками и добавленными полями. Вывод -- через исходный логгер.
+func (baselog *BaseLogger) With(fields ...Field) *BaseLogger {
+	child := &BaseLogger{
+		MaxMessageLen: baselog.MaxMessageLen,
+		RawText:       baselog.RawText,
+		ToJson:        baselog.ToJson,
+		Format:        baselog.Format,
+		Layout:        baselog.Layout,
+		TraceKey:      baselog.TraceKey,
+		TimeFormat:    baselog.TimeFormat,
+		Location:      baselog.Location,
+		Name:          baselog.Name,
+	}
+	child.root.Store(baselog.owner())
+	live := *baselog.settings()
+	live.fields = append(append(make([]Field, 0, len(live.fields)+len(fields)), live.fields...), fields...)
+	child.live.Store(&live)
+	return child
+}
+
 // NewBaseLogger -- генератор (в куче!) нового логгера из настроек @see ./config.go
 // param Args -- доп. параметры конфигуратора (если надо!): тут можно задать маршаллер в json
 func NewBaseLogger(cfg *LogConfig, args ...any) (Levelable, error) {
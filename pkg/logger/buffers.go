@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// Буферы записей -- в пулах по классам размеров: 1K, 4K, 16K, 64K, 256K. Буфер, выросший при форматировании,
+// возвращается в пул своего нового класса. Больше 256K в пулах не храним, чтобы одна огромная запись
+// не держала память навсегда.
+const (
+	bufSize    = 1024
+	bufClasses = 5
+	maxBufSize = bufSize << (2 * (bufClasses - 1))
+
+	// bufReserve -- запас к длине сообщения на уровень, время, место вызова и поля
+	bufReserve = 256
+
+	// maxStackDepth -- наибольшая глубина стека вызовов в записи @see BaseLogger.StackLevel
+	maxStackDepth = 64
+
+	// TruncatedMark -- пометка обрезанного сообщения, далее число отброшенных байт @see BaseLogger.MaxMessageLen
+	TruncatedMark = "...[truncated "
+)
+
+// logEntry -- буфер записи вместе с ее разбором, чтобы не аллоцировать запись на каждый вывод
+type logEntry struct {
+	buf   []byte
+	rec   LogRecord
+	stack [maxStackDepth]uintptr
+}
+
+var bufPools [bufClasses]sync.Pool
+
+// getEntry -- запись из пула наименьшего класса, вмещающего сообщение заданной длины
+func getEntry(size int) *logEntry {
+	size += bufReserve
+	class := 0
+	for class < bufClasses-1 && bufSize<<(2*class) < size {
+		class++
+	}
+	if entry, ok := bufPools[class].Get().(*logEntry); ok {
+		return entry
+	}
+	return &logEntry{buf: make([]byte, 0, bufSize<<(2*class))}
+}
+
+// putEntry -- возврат записи в пул по фактической емкости ее буфера. Слишком большие -- сборщику мусора.
+func putEntry(entry *logEntry) {
+	size := cap(entry.buf)
+	if size > maxBufSize {
+		return
+	}
+	class := bufClasses - 1
+	for class > 0 && bufSize<<(2*class) > size {
+		class--
+	}
+	entry.buf = entry.buf[:0]
+	entry.rec = LogRecord{}
+	bufPools[class].Put(entry)
+}
+
+// TruncateMessage -- обрезает сообщение до maxLen байт по границе символа UTF-8 и добавляет пометку
+// "...[truncated N bytes]", где N -- число отброшенных байт
+func TruncateMessage(message string, maxLen int) string {
+	if len(message) <= maxLen {
+		return message
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + TruncatedMark + strconv.Itoa(len(message)-cut) + " bytes]"
+}
@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Режимы раскраски вывода @see LogConfig.Color
+const (
+	ColorAuto   = "auto"   // только если вывод -- терминал
+	ColorAlways = "always" // всегда, например FORCE_COLOR
+	ColorNever  = "never"  // никогда, например NO_COLOR
+)
+
+// ColorTheme -- тема раскраски текстового лога: ESC-последовательности начала цвета частей записи.
+// Пустая строка -- часть не раскрашивается.
+type ColorTheme struct {
+	// Levels -- цвета по префиксу уровня LogPanicPrefix..LogDebugPrefix
+	Levels map[string]string
+	Time   string
+	Caller string
+	// Key -- цвет ключей полей
+	Key string
+}
+
+// LevelColor -- цвет уровня, nil тема -- без цвета
+func (theme *ColorTheme) LevelColor(level string) string {
+	if theme == nil {
+		return ""
+	}
+	return theme.Levels[level]
+}
+
+// TimeColor, CallerColor, KeyColor -- цвета частей записи, nil тема -- без цвета
+func (theme *ColorTheme) TimeColor() string {
+	if theme == nil {
+		return ""
+	}
+	return theme.Time
+}
+func (theme *ColorTheme) CallerColor() string {
+	if theme == nil {
+		return ""
+	}
+	return theme.Caller
+}
+func (theme *ColorTheme) KeyColor() string {
+	if theme == nil {
+		return ""
+	}
+	return theme.Key
+}
+
+// Color256 -- ESC-последовательность цвета текста из палитры 256 цветов
+func Color256(index int) string {
+	return EscStart + Esc256Sym + ";" + strconv.Itoa(index) + EscColorEnd
+}
+
+// ColorRGB -- ESC-последовательность цвета текста true color
+func ColorRGB(r, g, b int) string {
+	return EscStart + EscRGBSym + ";" + strconv.Itoa(r) + ";" + strconv.Itoa(g) + ";" + strconv.Itoa(b) + EscColorEnd
+}
+
+// DefaultTheme -- тема по умолчанию, уровни -- из GlDefColors
+var DefaultTheme = &ColorTheme{
+	Levels: GlDefColors,
+	Time:   EscStart + EscDark + EscColorEnd,
+	Caller: EscCyanCurrent,
+	Key:    EscBlueCurrent,
+}
+
+// GlThemes -- именованные темы для LOG_THEME, можно дополнять своими
+var GlThemes = map[string]*ColorTheme{
+	"default": DefaultTheme,
+	"256": {
+		Levels: map[string]string{
+			LogPanicPrefix: EscStart + EscBold + EscColorEnd + Color256(201),
+			LogFatalPrefix: EscStart + EscBold + EscColorEnd + Color256(196),
+			LogErrorPrefix: Color256(196),
+			LogWarnPrefix:  Color256(214),
+			LogInfoPrefix:  Color256(40),
+			LogDebugPrefix: Color256(33),
+		},
+		Time:   Color256(244),
+		Caller: Color256(110),
+		Key:    Color256(67),
+	},
+	"rgb": {
+		Levels: map[string]string{
+			LogPanicPrefix: EscStart + EscBold + EscColorEnd + ColorRGB(255, 0, 255),
+			LogFatalPrefix: EscStart + EscBold + EscColorEnd + ColorRGB(255, 40, 40),
+			LogErrorPrefix: ColorRGB(240, 80, 80),
+			LogWarnPrefix:  ColorRGB(250, 180, 50),
+			LogInfoPrefix:  ColorRGB(90, 200, 90),
+			LogDebugPrefix: ColorRGB(100, 150, 250),
+		},
+		Time:   ColorRGB(140, 140, 140),
+		Caller: ColorRGB(120, 170, 190),
+		Key:    ColorRGB(110, 130, 180),
+	},
+	"mono": {
+		Levels: map[string]string{
+			LogPanicPrefix: EscStart + EscInverse + EscColorEnd,
+			LogFatalPrefix: EscStart + EscInverse + EscColorEnd,
+			LogErrorPrefix: EscStart + EscBold + EscColorEnd,
+			LogWarnPrefix:  EscStart + EscUnderLine + EscColorEnd,
+		},
+		Time: EscStart + EscDark + EscColorEnd,
+	},
+}
+
+// themeParts -- части записи в описании темы и префиксы уровней
+var themeParts = map[string]string{
+	"panic": LogPanicPrefix,
+	"fatal": LogFatalPrefix,
+	"error": LogErrorPrefix,
+	"warn":  LogWarnPrefix,
+	"info":  LogInfoPrefix,
+	"debug": LogDebugPrefix,
+}
+
+// ParseTheme -- тема по имени из GlThemes или описанию поверх темы по умолчанию:
+// "error=196,warn=208,time=245,caller=100:100:255,key=33" -- часть=номер цвета 0..255 или r:g:b
+// Части: panic, fatal, error, warn, info, debug, time, caller, key. "" -- тема по умолчанию.
+func ParseTheme(spec string) (*ColorTheme, error) {
+	if spec == "" {
+		return DefaultTheme, nil
+	}
+	if theme, ok := GlThemes[spec]; ok {
+		return theme, nil
+	}
+
+	theme := &ColorTheme{
+		Levels: make(map[string]string, len(DefaultTheme.Levels)),
+		Time:   DefaultTheme.Time,
+		Caller: DefaultTheme.Caller,
+		Key:    DefaultTheme.Key,
+	}
+	for level, color := range DefaultTheme.Levels {
+		theme.Levels[level] = color
+	}
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("ParseTheme(): '%s' is not part=color", part)
+		}
+		color, err := parseColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("ParseTheme(): %s: %s", name, err.Error())
+		}
+		switch name {
+		case "time":
+			theme.Time = color
+		case "caller":
+			theme.Caller = color
+		case "key":
+			theme.Key = color
+		default:
+			level, ok := themeParts[name]
+			if !ok {
+				return nil, fmt.Errorf("ParseTheme(): unknown part '%s'", name)
+			}
+			theme.Levels[level] = color
+		}
+	}
+	return theme, nil
+}
+
+// parseColor -- "n" 0..255 -> Color256(), "r:g:b" -> ColorRGB()
+func parseColor(value string) (string, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 1 && len(parts) != 3 {
+		return "", fmt.Errorf("color '%s' is not n or r:g:b", value)
+	}
+	var nums [3]int
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil || num < 0 || num > 255 {
+			return "", fmt.Errorf("color '%s' has not 0..255 value", value)
+		}
+		nums[i] = num
+	}
+	if len(parts) == 1 {
+		return Color256(nums[0]), nil
+	}
+	return ColorRGB(nums[0], nums[1], nums[2]), nil
+}
+
+// IsColored -- раскрашивать ли вывод в этом режиме: auto -- только в терминал @see IsTerminal()
+func IsColored(mode string, out io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return IsTerminal(out)
+}
+
+// formatColorStart -- начало раскраски, если цвет задан. Возвращает цвет для formatColorEnd()
+func formatColorStart(buf *[]byte, color string) string {
+	*buf = append(*buf, color...)
+	return color
+}
+
+// formatColorEnd -- отмена раскраски, если она была начата
+func formatColorEnd(buf *[]byte, color string) {
+	if color != "" {
+		*buf = append(*buf, EscStart+EscReset+EscColorEnd...)
+	}
+}
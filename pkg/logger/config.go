@@ -1,19 +1,84 @@
 package logger
 
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 const (
+	// EnvLoggerOut -- куда выводить: "stderr"|"stdout"|"devnul"|путь к файлу, "" -- stderr
+	EnvLoggerOut = "LOG_OUT"
+	DefLoggerOut = ""
+
 	// EnvLoggerJson -- отдавать строкой или в JSON?
 	EnvLoggerJson = "LOG_JSON"
 	DefLoggerJson = "json"
 	DefLoggerText = "txt"
 
-	// EnvLoggerFlags -- формат вывода сообщений "работать молча"
+	// EnvLoggerFormat -- формат вывода: txt|json|logfmt. Если не задан, то берется из LOG_JSON
+	EnvLoggerFormat = "LOG_FORMAT"
+	DefLoggerLogfmt = "logfmt"
+
+	// EnvLoggerFlags -- флаги вывода: число (битовая маска) или список имен "date,time,micro,shortfile,color,trace"
+	// @see LogFlagNames, по умолчанию -- DefLoggerFlags
 	EnvLoggerFlags = "LOG_FLAGS"
-	DefLoggerFlags = -1
+	// DefLoggerFlags -- дата, время, файл#строка и раскраска уровня (только в терминал)
+	DefLoggerFlags = LogDate | LogTime | LogShortFile | LogLevelColored
 
-	// EnvLoggerLevel -- уровень вывода сообщений логгером:
+	// EnvLoggerLevel -- уровень вывода сообщений логгером: panic|fatal|error|warn|info|debug или число @see ToLevel()
 	EnvLoggerLevel = "LOG_LEVEL"
 	DefLoggerLevel = "info"
 
+	// EnvLoggerLayout -- шаблон строки текстового лога @see CompileLayout(), "" -- фиксированный формат
+	EnvLoggerLayout = "LOG_LAYOUT"
+	DefLoggerLayout = ""
+
+	// EnvLoggerTimeFormat -- формат времени: RFC3339|RFC3339Nano|unix|unixmilli|unixnano|isoweek|layout Go
+	// @see FormatTimestamp(), "" -- по флагам вывода
+	EnvLoggerTimeFormat = "LOG_TIME_FORMAT"
+	DefLoggerTimeFormat = ""
+
+	// EnvLoggerTimeZone -- часовой пояс времени записей: "UTC", "Local", "Europe/Moscow", .. "" -- местное
+	EnvLoggerTimeZone = "LOG_TIME_ZONE"
+	DefLoggerTimeZone = ""
+
+	// EnvLoggerStackLevel -- к записям этого уровня и важнее добавляется стек вызовов, "" -- без стека
+	EnvLoggerStackLevel = "LOG_STACK_LEVEL"
+	DefLoggerStackLevel = "error"
+
+	// EnvLoggerMaxMessage -- наибольшая длина сообщения в байтах, длиннее -- обрезается с пометкой. 0 -- без ограничения
+	EnvLoggerMaxMessage = "LOG_MAX_MESSAGE"
+	DefLoggerMaxMessage = 0
+
+	// EnvLoggerRawText -- "true": текст сообщений как есть, без экранирования управляющих символов и ESC-последовательностей
+	EnvLoggerRawText = "LOG_RAW_TEXT"
+	DefLoggerRawText = "false"
+
+	// EnvLoggerColor -- раскраска текстового лога по флагу LogLevelColored: auto|always|never, auto -- только в терминал
+	EnvLoggerColor = "LOG_COLOR"
+	DefLoggerColor = ColorAuto
+	// EnvNoColor, EnvForceColor -- общепринятые переменные (no-color.org), важнее LOG_COLOR: непустое значение -- never|always
+	EnvNoColor    = "NO_COLOR"
+	EnvForceColor = "FORCE_COLOR"
+
+	// EnvLoggerTheme -- тема раскраски: default|256|rgb|mono или описание "error=196,time=245,key=100:100:255" @see ParseTheme()
+	EnvLoggerTheme = "LOG_THEME"
+	DefLoggerTheme = ""
+
+	// EnvLoggerOutputs -- дополнительные выводы (sinks) через запятую: "stdout,/var/log/app.log", "" -- только основной
+	EnvLoggerOutputs = "LOG_OUTPUTS"
+	DefLoggerOutputs = ""
+
+	// EnvLoggerRotateSize -- ротация файлов лога по размеру в байтах @see RotateFile, 0 -- без ротации
+	EnvLoggerRotateSize = "LOG_ROTATE_SIZE"
+	DefLoggerRotateSize = 0
+	// EnvLoggerRotateBackups -- сколько старых файлов хранить при ротации
+	EnvLoggerRotateBackups = "LOG_ROTATE_BACKUPS"
+	DefLoggerRotateBackups = 3
+
 	// EnvTraceId -- идентификатор сквозной трассировки, если не типовой
 	EnvTraceId = "LOG_TRACE_ID"
 	DefTraceId = CtxTraceId
@@ -22,44 +87,222 @@ const (
 // LogConfig -- настройки для инициализации логгеров. Одна структура на все типы.
 // Если чего-то у логгера нет, то можно проигнорировать данный параметр тут.
 type LogConfig struct {
+	// EnvPrefix префикс имен переменных окружения этого логгера: "DB_" -- сначала DB_LOG_LEVEL, затем LOG_LEVEL
+	// Позволяет нескольким логгерам в одном процессе читать разные переменные @see NewLogConfigPrefix()
+	EnvPrefix string
 	// "" по умолчанию в stderr, иначе полный путь к файлу лога или "stdout"|"devnul"
 	Out string
 	// IsJson формировать лог в JSON (true) или строками (false)?
 	IsJson bool
+	// Format формат вывода "txt"|"json"|"logfmt", "" -- определяется по IsJson
+	Format string
 	// Flags флаги отображения. ==0 вывод только сообщения через Sprintf
 	Flags int
 	// Level std: 60 - debug, 50 - info, 40 - warn, 30 - error, 20 - fatal, 10 - panic, <10 не выводим ничего.
 	// позволяет в обертках применить расширение уровней на свое усмотрение..
 	Level int
+	// StackLevel к записям этого уровня и важнее (меньше) добавляется стек вызовов, LogNoneLevel -- без стека
+	StackLevel int
+	// RawText текст сообщений как есть (true) или с экранированием управляющих символов (false, по умолчанию)
+	RawText bool
+	// MaxMessageLen наибольшая длина сообщения в байтах, длиннее -- обрезается с пометкой. 0 -- без ограничения
+	MaxMessageLen int
+	// Layout шаблон строки текстового лога @see CompileLayout(), "" -- фиксированный формат FormatString()
+	Layout string
+	// TimeFormat формат времени @see FormatTimestamp(), "" -- по флагам
+	TimeFormat string
+	// TimeZone часовой пояс для time.LoadLocation(), "" -- местное время или UTC по флагу LogUTC
+	TimeZone string
+	// Outputs дополнительные выводы (sinks) в том же формате, что и Out
+	Outputs []string
+	// RotateSize ротация файлов лога по размеру в байтах, 0 -- без ротации
+	RotateSize int
+	// RotateBackups сколько старых файлов хранить при ротации
+	RotateBackups int
+	// Color раскраска по флагу LogLevelColored: "auto"|"always"|"never", "" -- как auto
+	Color string
+	// Theme имя темы раскраски или ее описание @see ParseTheme(), "" -- тема по умолчанию
+	Theme string
+	// problems ошибочные значения переменных окружения, найденные Init() @see Validate()
+	problems []*ConfigError
 	// TraceId идент сквозной трассировки. Может приходить в контексте для CtxLogger
 	TraceId string
 }
 
 // Init -- формирование настроек. Возвращает this
+// Ошибочные значения переменных заменяются нулевыми и попадают в диагностику, проверка -- Validate()
 func (cfg *LogConfig) Init() *LogConfig {
-	cfg.IsJson = ToString(LookupEnv(EnvLoggerJson, DefLoggerText)) == DefLoggerJson
-	cfg.Flags = ToInt(LookupEnv(EnvLoggerFlags, DefLoggerFlags))
-	cfg.TraceId = ToString(LookupEnv(EnvTraceId, DefTraceId))
+	cfg.problems = nil
+	cfg.Out = ToString(cfg.LookupEnv(EnvLoggerOut, DefLoggerOut))
+	cfg.Format = ToString(cfg.LookupEnv(EnvLoggerFormat, cfg.LookupEnv(EnvLoggerJson, DefLoggerText)))
+	cfg.IsJson = cfg.Format == DefLoggerJson
+	cfg.Flags = cfg.envFlags("Flags", EnvLoggerFlags, DefLoggerFlags)
+	cfg.RawText = ToString(cfg.LookupEnv(EnvLoggerRawText, DefLoggerRawText)) == "true"
+	cfg.MaxMessageLen = cfg.envInt("MaxMessageLen", EnvLoggerMaxMessage, DefLoggerMaxMessage)
+	cfg.Layout = ToString(cfg.LookupEnv(EnvLoggerLayout, DefLoggerLayout))
+	cfg.TimeFormat = ToString(cfg.LookupEnv(EnvLoggerTimeFormat, DefLoggerTimeFormat))
+	cfg.TimeZone = ToString(cfg.LookupEnv(EnvLoggerTimeZone, DefLoggerTimeZone))
+	cfg.TraceId = ToString(cfg.LookupEnv(EnvTraceId, DefTraceId))
+	cfg.Outputs = SplitList(ToString(cfg.LookupEnv(EnvLoggerOutputs, DefLoggerOutputs)))
+	cfg.RotateSize = cfg.envInt("RotateSize", EnvLoggerRotateSize, DefLoggerRotateSize)
+	cfg.RotateBackups = cfg.envInt("RotateBackups", EnvLoggerRotateBackups, DefLoggerRotateBackups)
+	cfg.Color = ToString(cfg.LookupEnv(EnvLoggerColor, DefLoggerColor))
+	if force := ToString(LookupEnv(EnvForceColor, "")); force != "" && force != "0" {
+		cfg.Color = ColorAlways
+	}
+	if ToString(LookupEnv(EnvNoColor, "")) != "" {
+		cfg.Color = ColorNever
+	}
+	cfg.Theme = ToString(cfg.LookupEnv(EnvLoggerTheme, DefLoggerTheme))
 
-	strLevel := ToString(LookupEnv(EnvLoggerLevel, DefLoggerLevel))
-	var level int
+	cfg.Level = cfg.envLevel("Level", EnvLoggerLevel, DefLoggerLevel, false)
+	cfg.StackLevel = cfg.envLevel("StackLevel", EnvLoggerStackLevel, DefLoggerStackLevel, true)
+
+	return cfg
+}
+
+// LookupEnv -- значение переменной окружения с префиксом EnvPrefix, затем без него, иначе -- по умолчанию
+func (cfg *LogConfig) LookupEnv(name string, defVal interface{}) interface{} {
+	if cfg.EnvPrefix != "" {
+		if val, ok := os.LookupEnv(cfg.EnvPrefix + name); ok {
+			return val
+		}
+	}
+	return LookupEnv(name, defVal)
+}
+
+// isEnvSet -- задана ли переменная окружения с префиксом или без него
+func (cfg *LogConfig) isEnvSet(name string) bool {
+	_, ok := os.LookupEnv(name)
+	if !ok && cfg.EnvPrefix != "" {
+		_, ok = os.LookupEnv(cfg.EnvPrefix + name)
+	}
+	return ok
+}
+
+// ToLevel -- уровень по имени "panic".."debug", префиксу LogPanicPrefix.. или числом ("50" -- info),
+// неизвестное -- LogNoneLevel
+func ToLevel(strLevel string) int {
 	switch strLevel {
 	case "panic", LogPanicPrefix:
-		level = LogPanicLevel
+		return LogPanicLevel
 	case "fatal", LogFatalPrefix:
-		level = LogFatalLevel
+		return LogFatalLevel
 	case "error", LogErrorPrefix:
-		level = LogErrorLevel
+		return LogErrorLevel
 	case "warn", LogWarnPrefix:
-		level = LogWarnLevel
+		return LogWarnLevel
 	case "info", LogInfoPrefix:
-		level = LogInfoLevel
+		return LogInfoLevel
 	case "debug", LogDebugPrefix:
-		level = LogDebugLevel
+		return LogDebugLevel
+	}
+	if level, err := strconv.Atoi(strLevel); err == nil && level > LogNoneLevel {
+		return level
 	}
-	cfg.Level = level
+	return LogNoneLevel
+}
 
-	return cfg
+// LevelPrefix -- префикс записи для уровня, обратное ToLevel(). Промежуточные уровни -- к ближайшему более важному
+func LevelPrefix(level int) string {
+	switch {
+	case level <= LogPanicLevel:
+		return LogPanicPrefix
+	case level <= LogFatalLevel:
+		return LogFatalPrefix
+	case level <= LogErrorLevel:
+		return LogErrorPrefix
+	case level <= LogWarnLevel:
+		return LogWarnPrefix
+	case level <= LogInfoLevel:
+		return LogInfoPrefix
+	}
+	return LogDebugPrefix
+}
+
+// LogFlagNames -- имена флагов вывода для настроек списком @see ParseFlags()
+var LogFlagNames = map[string]int{
+	"shortfile": LogShortFile,
+	"longfile":  LogLongFile,
+	"func":      LogFuncName,
+	"utc":       LogUTC,
+	"date":      LogDate,
+	"time":      LogTime,
+	"micro":     LogMicroSeconds,
+	"color":     LogLevelColored,
+	"trace":     LogWithTrace,
+	"prefix":    LogPrefix,
+	"std":       BaseDefFlags,
+	"all":       LogAllFlags,
+}
+
+// ParseFlags -- флаги вывода по списку имен LogFlagNames, неизвестное имя -- ошибка со списком допустимых
+func ParseFlags(names []string) (int, error) {
+	flags, unknown := parseFlagNames(names)
+	if len(unknown) > 0 {
+		return 0, fmt.Errorf("ParseFlags(): unknown flag '%s', expected one of %s", unknown[0], strings.Join(flagNames(), ","))
+	}
+	return flags, nil
+}
+
+// parseFlags -- флаги из числа или списка имен через запятую, общий разбор для ToFlags() и настроек из окружения.
+// Отдает флаги известных имен и отдельно неизвестные имена
+func parseFlags(val interface{}) (int, []string) {
+	str, ok := val.(string)
+	if !ok {
+		return ToInt(val), nil
+	}
+	if flags, err := strconv.Atoi(strings.TrimSpace(str)); err == nil {
+		return flags, nil
+	}
+	return parseFlagNames(SplitList(str))
+}
+
+// parseFlagNames -- флаги по именам LogFlagNames без учета регистра, неизвестные имена -- отдельно
+func parseFlagNames(names []string) (flags int, unknown []string) {
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			continue
+		}
+		flag, ok := LogFlagNames[key]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		flags |= flag
+	}
+	return flags, unknown
+}
+
+func flagNames() []string {
+	names := make([]string, 0, len(LogFlagNames))
+	for name := range LogFlagNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ToFlags -- флаги вывода из числа или списка имен через запятую @see ParseFlags(), ошибка -- в диагностику @see Diagnose()
+func ToFlags(val interface{}) int {
+	flags, unknown := parseFlags(val)
+	if len(unknown) > 0 {
+		Diagnose(fmt.Errorf("ToFlags() ERROR! unknown flag '%s', expected one of %s", unknown[0], strings.Join(flagNames(), ",")))
+		return 0
+	}
+	return flags
+}
+
+// SplitList -- список через запятую без пробелов и пустых элементов
+func SplitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // NewLogConfig returns application config instance
@@ -67,3 +310,10 @@ func NewLogConfig() *LogConfig {
 	cfg := LogConfig{}
 	return (&cfg).Init()
 }
+
+// NewLogConfigPrefix -- настройки из переменных окружения с префиксом, например "DB_" -- DB_LOG_LEVEL, DB_LOG_OUT..
+// Не заданные с префиксом берутся из общих LOG_LEVEL, LOG_OUT..
+func NewLogConfigPrefix(prefix string) *LogConfig {
+	cfg := LogConfig{EnvPrefix: prefix}
+	return (&cfg).Init()
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig -- настройки логгера в файле JSON, YAML или TOML. Пустые (не заданные) значения не применяются.
+// Пример YAML:
+//
+//	out: /var/log/app.log
+//	outputs: [stdout]
+//	format: logfmt
+//	level: debug
+//	flags: [date, time, shortfile]
+//	rotate: {max_size: 10485760, max_backups: 5}
+type FileConfig struct {
+	Out           string     `json:"out" yaml:"out" toml:"out"`
+	Outputs       []string   `json:"outputs" yaml:"outputs" toml:"outputs"`
+	Format        string     `json:"format" yaml:"format" toml:"format"`
+	Level         string     `json:"level" yaml:"level" toml:"level"`
+	StackLevel    string     `json:"stack_level" yaml:"stack_level" toml:"stack_level"`
+	Flags         []string   `json:"flags" yaml:"flags" toml:"flags"`
+	Layout        string     `json:"layout" yaml:"layout" toml:"layout"`
+	TimeFormat    string     `json:"time_format" yaml:"time_format" toml:"time_format"`
+	TimeZone      string     `json:"time_zone" yaml:"time_zone" toml:"time_zone"`
+	Color         string     `json:"color" yaml:"color" toml:"color"`
+	Theme         string     `json:"theme" yaml:"theme" toml:"theme"`
+	TraceId       string     `json:"trace_id" yaml:"trace_id" toml:"trace_id"`
+	MaxMessageLen *int       `json:"max_message" yaml:"max_message" toml:"max_message"`
+	RawText       *bool      `json:"raw_text" yaml:"raw_text" toml:"raw_text"`
+	Rotate        FileRotate `json:"rotate" yaml:"rotate" toml:"rotate"`
+}
+
+// FileRotate -- ротация файлов лога @see RotateFile
+type FileRotate struct {
+	MaxSize    *int `json:"max_size" yaml:"max_size" toml:"max_size"`
+	MaxBackups *int `json:"max_backups" yaml:"max_backups" toml:"max_backups"`
+}
+
+// LoadConfigFile -- настройки из файла, формат по расширению: .json, .yaml|.yml, .toml
+// Порядок важности: переменные окружения, затем файл, затем умолчания @see LogConfig.Init()
+func LoadConfigFile(path string) (*LogConfig, error) {
+	cfg := NewLogConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadFile -- дополнение настроек, прочитанных из окружения (с префиксом EnvPrefix), значениями из файла
+// Значения, заданные переменными окружения, не меняются @see LoadConfigFile()
+func (cfg *LogConfig) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("LoadConfigFile() has: %s", err.Error())
+	}
+
+	var file FileConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".toml":
+		err = toml.Unmarshal(data, &file)
+	default:
+		return fmt.Errorf("LoadConfigFile(): unknown config format '%s' of %s", ext, path)
+	}
+	if err != nil {
+		return fmt.Errorf("LoadConfigFile() %s has: %s", path, err.Error())
+	}
+	if err = file.Apply(cfg); err != nil {
+		return fmt.Errorf("LoadConfigFile() %s has: %w", path, err)
+	}
+	return nil
+}
+
+// Apply -- перенос заданных в файле значений в настройки, кроме тех, что заданы переменными окружения
+func (file *FileConfig) Apply(cfg *LogConfig) error {
+	applyString(cfg, &cfg.Out, file.Out, EnvLoggerOut)
+	if file.Outputs != nil && !cfg.isEnvSet(EnvLoggerOutputs) {
+		cfg.Outputs = file.Outputs
+	}
+	if file.Format != "" && !cfg.isEnvSet(EnvLoggerFormat) && !cfg.isEnvSet(EnvLoggerJson) {
+		switch file.Format {
+		case DefLoggerText, DefLoggerJson, DefLoggerLogfmt:
+		default:
+			return fmt.Errorf("format '%s' is not %s|%s|%s", file.Format, DefLoggerText, DefLoggerJson, DefLoggerLogfmt)
+		}
+		cfg.Format = file.Format
+		cfg.IsJson = file.Format == DefLoggerJson
+	}
+	if file.Level != "" && !cfg.isEnvSet(EnvLoggerLevel) {
+		if cfg.Level = ToLevel(file.Level); cfg.Level == LogNoneLevel && file.Level != "0" {
+			return fmt.Errorf("unknown level '%s'", file.Level)
+		}
+	}
+	if file.StackLevel != "" && !cfg.isEnvSet(EnvLoggerStackLevel) {
+		cfg.StackLevel = ToLevel(file.StackLevel)
+	}
+	if file.Flags != nil && !cfg.isEnvSet(EnvLoggerFlags) {
+		flags, unknown := parseFlagNames(file.Flags)
+		if len(unknown) > 0 {
+			return &ConfigError{Field: "Flags", Value: strings.Join(file.Flags, ","),
+				Reason: fmt.Sprintf("unknown flag '%s'", unknown[0]), Suggestion: suggest(unknown[0], flagNames())}
+		}
+		cfg.Flags = flags
+	}
+	applyString(cfg, &cfg.Layout, file.Layout, EnvLoggerLayout)
+	applyString(cfg, &cfg.TimeFormat, file.TimeFormat, EnvLoggerTimeFormat)
+	applyString(cfg, &cfg.TimeZone, file.TimeZone, EnvLoggerTimeZone)
+	applyString(cfg, &cfg.Theme, file.Theme, EnvLoggerTheme)
+	applyString(cfg, &cfg.TraceId, file.TraceId, EnvTraceId)
+	if file.Color != "" && !cfg.isEnvSet(EnvLoggerColor) && !cfg.isEnvSet(EnvNoColor) && !cfg.isEnvSet(EnvForceColor) {
+		cfg.Color = file.Color
+	}
+	if file.MaxMessageLen != nil && !cfg.isEnvSet(EnvLoggerMaxMessage) {
+		cfg.MaxMessageLen = *file.MaxMessageLen
+	}
+	if file.RawText != nil && !cfg.isEnvSet(EnvLoggerRawText) {
+		cfg.RawText = *file.RawText
+	}
+	if file.Rotate.MaxSize != nil && !cfg.isEnvSet(EnvLoggerRotateSize) {
+		cfg.RotateSize = *file.Rotate.MaxSize
+	}
+	if file.Rotate.MaxBackups != nil && !cfg.isEnvSet(EnvLoggerRotateBackups) {
+		cfg.RotateBackups = *file.Rotate.MaxBackups
+	}
+	return nil
+}
+
+func applyString(cfg *LogConfig, dst *string, val, env string) {
+	if val != "" && !cfg.isEnvSet(env) {
+		*dst = val
+	}
+}
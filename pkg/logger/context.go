@@ -0,0 +1,85 @@
+package logger
+
+import "context"
+
+// TraceCtxKey -- тип ключа значения трассировки в контексте. Свой тип не пересекается со строковыми ключами
+// других пакетов, само значение ключа -- BaseLogger.TraceKey (по умолчанию CtxTraceId)
+type TraceCtxKey string
+
+// ContextWithTraceId -- контекст со значением трассировки под ключом key @see TraceIdFromContext()
+func ContextWithTraceId(ctx context.Context, key, traceId string) context.Context {
+	return context.WithValue(ctx, TraceCtxKey(key), traceId)
+}
+
+// ParseTraceparent -- trace-id из заголовка W3C "00-<32 hex trace-id>-<16 hex parent-id>-<2 hex flags>".
+// Нулевой trace-id и версия ff недопустимы по спецификации.
+func ParseTraceparent(header string) (string, bool) {
+	if len(header) < 55 || header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return "", false
+	}
+	version, traceId, parentId, flags := header[:2], header[3:35], header[36:52], header[53:55]
+	if version == "ff" || (version == "00" && len(header) != 55) {
+		return "", false
+	}
+	if !isLowerHex(version) || !isLowerHex(traceId) || !isLowerHex(parentId) || !isLowerHex(flags) {
+		return "", false
+	}
+	if traceId == "00000000000000000000000000000000" || parentId == "0000000000000000" {
+		return "", false
+	}
+	return traceId, true
+}
+
+// TraceIdFromContext -- значение трассировки из контекста или "", если его нет.
+// Ищется по типизированному ключу, затем по простой строке key, как его клали раньше.
+func TraceIdFromContext(ctx context.Context, key string) string {
+	if traceId, ok := ctx.Value(TraceCtxKey(key)).(string); ok {
+		return traceId
+	}
+	if traceId, ok := ctx.Value(key).(string); ok {
+		return traceId
+	}
+	return ""
+}
+
+// ContextWithTrace -- контекст со значением трассировки под ключом этого логгера
+func (baselog *BaseLogger) ContextWithTrace(ctx context.Context, traceId string) context.Context {
+	return ContextWithTraceId(ctx, baselog.GetTraceKey(), traceId)
+}
+
+// TraceId -- значение трассировки из контекста по ключу этого логгера
+func (baselog *BaseLogger) TraceId(ctx context.Context) string {
+	return TraceIdFromContext(ctx, baselog.GetTraceKey())
+}
+
+// TraceField -- поле трассировки из контекста для OutlogFields(), ok=false -- его там нет
+func (baselog *BaseLogger) TraceField(ctx context.Context) (Field, bool) {
+	traceId := baselog.TraceId(ctx)
+	return Field{Key: baselog.GetTraceKey(), Value: traceId}, traceId != ""
+}
+
+// Ctx -- логгер (в куче!) с полем трассировки из контекста, если оно там есть, иначе -- этот же
+func (baselog *BaseLogger) Ctx(ctx context.Context) *BaseLogger {
+	if field, ok := baselog.TraceField(ctx); ok {
+		return baselog.With(field)
+	}
+	return baselog
+}
+
+// GetTraceKey -- действующий ключ трассировки: TraceKey логгера (у логгеров узлов иерархии -- корня)
+// или CtxTraceId, если логгер собран без Init()
+func (baselog *BaseLogger) GetTraceKey() string {
+	if traceKey := baselog.formatter().TraceKey; traceKey != "" {
+		return traceKey
+	}
+	return CtxTraceId
+}
+
+func isLowerHex(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if c := str[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
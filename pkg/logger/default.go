@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// glDefault -- логгер процесса по умолчанию для функций пакета Info(), Errorf() и т.д.
+// Читается атомарно, замена -- под мьютексом вместе с корнем иерархии @see SetDefault()
+var (
+	glDefault   atomic.Pointer[BaseLogger]
+	glDefaultMu sync.Mutex
+)
+
+// Default -- логгер процесса: корень иерархии Named(), при первом обращении создается из NewLogConfig()
+func Default() *BaseLogger {
+	if lgr := glDefault.Load(); lgr != nil {
+		return lgr
+	}
+	glDefault.CompareAndSwap(nil, DefaultRegistry().Root())
+	return glDefault.Load()
+}
+
+// SetDefault -- атомарная замена логгера процесса, он же становится корнем иерархии Named(). Отдает прежний.
+// Можно во время записи: логгеры Named() переходят на новый корень без гонок @see Registry.SetRoot().
+// nil не допускается, замена игнорируется.
+func SetDefault(lgr *BaseLogger) *BaseLogger {
+	old := Default()
+	if lgr == nil {
+		return old
+	}
+	glDefaultMu.Lock()
+	defer glDefaultMu.Unlock()
+	old = glDefault.Swap(lgr)
+	DefaultRegistry().SetRoot(lgr)
+	return old
+}
+
+// Функции пакета пишут в логгер процесса, место вызова -- вызвавший их код. Формат сообщения -- как у fmt.Sprintf()
+
+func Debug(msg string, args ...any) { outDefault(LogDebugLevel, LogDebugPrefix, msg, args) }
+func Info(msg string, args ...any)  { outDefault(LogInfoLevel, LogInfoPrefix, msg, args) }
+func Warn(msg string, args ...any)  { outDefault(LogWarnLevel, LogWarnPrefix, msg, args) }
+func Error(msg string, args ...any) { outDefault(LogErrorLevel, LogErrorPrefix, msg, args) }
+
+// Debugf .. Errorf -- то же, для привычных по пакетам log и fmt имен
+
+func Debugf(format string, args ...any) { outDefault(LogDebugLevel, LogDebugPrefix, format, args) }
+func Infof(format string, args ...any)  { outDefault(LogInfoLevel, LogInfoPrefix, format, args) }
+func Warnf(format string, args ...any)  { outDefault(LogWarnLevel, LogWarnPrefix, format, args) }
+func Errorf(format string, args ...any) { outDefault(LogErrorLevel, LogErrorPrefix, format, args) }
+
+func Fatal(msg string, args ...any) {
+	if lgr := Default(); lgr.GetLevel() >= LogFatalLevel {
+		lgr.Outlog(1, time.Now(), LogFatalPrefix, fmt.Sprintf(msg, args...))
+		lgr.RunExitHooks()
+		os.Exit(1)
+	}
+}
+func Panic(msg string, args ...any) {
+	if lgr := Default(); lgr.GetLevel() >= LogPanicLevel {
+		message := fmt.Sprintf(msg, args...)
+		lgr.Outlog(1, time.Now(), LogPanicPrefix, message)
+		panic(message)
+	}
+}
+
+// WarnE, ErrorE -- запись с полем ошибки Err(err) @see BaseLogger.ErrorE()
+
+func WarnE(err error, msg string, args ...any) {
+	outDefaultE(LogWarnLevel, LogWarnPrefix, err, msg, args)
+}
+func ErrorE(err error, msg string, args ...any) {
+	outDefaultE(LogErrorLevel, LogErrorPrefix, err, msg, args)
+}
+
+// With -- логгер процесса с добавленными полями @see BaseLogger.With()
+func With(fields ...Field) *BaseLogger { return Default().With(fields...) }
+
+// outDefault -- глубина 2: над outDefault() функция пакета, над ней -- место вызова в коде пользователя
+func outDefault(level int, prefix, msg string, args []any) {
+	if lgr := Default(); lgr.GetLevel() >= level {
+		lgr.Outlog(2, time.Now(), prefix, fmt.Sprintf(msg, args...))
+	}
+}
+
+func outDefaultE(level int, prefix string, err error, msg string, args []any) {
+	if lgr := Default(); lgr.GetLevel() >= level {
+		lgr.OutlogFields(2, time.Now(), prefix, fmt.Sprintf(msg, args...), []Field{Err(err)})
+	}
+}
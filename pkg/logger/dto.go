@@ -33,14 +33,25 @@ const (
 	LogInfoPrefix  = "INFO "
 	LogDebugPrefix = "DEBUG"
 
+	// LogFormatText .. LogFormatLogfmt -- формат вывода записи лога @see BaseLogger.Format
+	LogFormatText   = 0
+	LogFormatJson   = 1
+	LogFormatLogfmt = 2
+
 	// LogFatalExitCode -- число, отдаваемое ОС при завершении программы из ...Fatal() вызовов.
 	LogFatalExitCode = 500
 
 	// CtxTraceId -- упрощенная версия идентификатора значений сквозного лога
 	CtxTraceId = "trace_id"
 
+	// NameKey -- ключ имени логгера в logfmt и json по флагу LogPrefix @see Named()
+	NameKey = "logger"
+
 	// BaseDefFlags местный стандарт формата вывода:
 	BaseDefFlags = LogUTC | LogMicroSeconds | LogShortFile
+	// LogAllFlags -- все флаги вывода ("all")
+	LogAllFlags = LogShortFile | LogLongFile | LogFuncName | LogUTC | LogDate | LogTime | LogMicroSeconds |
+		LogLevelColored | LogWithTrace | LogPrefix
 
 	// @see New():
 	// Нумерация дополнительных параметров конструктору логера в требуемом ему порядке:
@@ -57,6 +68,28 @@ const (
 // LogJsonHandler -- обработчик преобразования сообщения в json м.б. внешним
 type LogJsonHandler func(buf *[]byte, depth int, now time.Time, level, message string) error
 
+// Field -- дополнительное именованное поле записи лога @see BaseLogger.With()
+type Field struct {
+	Key   string
+	Value any
+}
+
+// LogRecord -- запись лога на время форматирования. Живет в пуле вместе с буфером, ссылки на нее не сохранять!
+type LogRecord struct {
+	// Depth -- глубина вызовов до места логирования от функции, вызвавшей форматирование (как depth в Outlog)
+	Depth   int
+	Now     time.Time
+	Level   string
+	Message string
+	// Fields -- поля этой записи, выводятся после полей логгера
+	Fields []Field
+	// Stack -- стек вызовов места логирования, если нужен по уровню @see BaseLogger.StackLevel
+	Stack []uintptr
+}
+
+// LogAppender -- добавлялка части записи в буфер, элемент скомпилированного шаблона @see CompileLayout()
+type LogAppender func(buf *[]byte, baselog *BaseLogger, rec *LogRecord)
+
 // Loggable -- Тот, кто умеет выводить сообщения разного уровня в логгер:
 type Loggable interface {
 	// GetLevel -- получить наименьший разрешенный уровень сообщений
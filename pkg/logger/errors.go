@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErrorKey -- ключ поля ошибки @see Err()
+const ErrorKey = "error"
+
+// maxErrorDepth -- ограничение глубины разбора цепочек ошибок (на случай зацикливания Unwrap())
+const maxErrorDepth = 32
+
+// ErrorFielder -- ошибка, добавляющая в запись свои структурированные поля (коды, идентификаторы и т.п.)
+type ErrorFielder interface {
+	ErrorFields() []Field
+}
+
+// ErrorFieldsHook -- внешний поставщик полей ошибки, для ошибок чужих пакетов (например gRPC status)
+type ErrorFieldsHook func(err error) []Field
+
+var (
+	errorHooksMu sync.RWMutex
+	errorHooks   []ErrorFieldsHook
+)
+
+// RegisterErrorFields -- добавить поставщика полей ошибок. Вызывается из init() пакетов-расширений.
+func RegisterErrorFields(hook ErrorFieldsHook) {
+	errorHooksMu.Lock()
+	errorHooks = append(errorHooks, hook)
+	errorHooksMu.Unlock()
+}
+
+// Err -- поле ошибки для записи: в тексте -- сообщение и цепочка типов причин, в json -- объект с причинами
+func Err(err error) Field {
+	return Field{Key: ErrorKey, Value: err}
+}
+
+// ErrorFields -- структурированные поля ошибки: от первой в цепочке ErrorFielder и зарегистрированных поставщиков
+func ErrorFields(err error) []Field {
+	var fields []Field
+	var fielder ErrorFielder
+	if errors.As(err, &fielder) {
+		fields = append(fields, fielder.ErrorFields()...)
+	}
+	return append(fields, errorHookFields(err)...)
+}
+
+// errorHookFields -- поля ошибки от зарегистрированных поставщиков
+func errorHookFields(err error) []Field {
+	var fields []Field
+	errorHooksMu.RLock()
+	for _, hook := range errorHooks {
+		fields = append(fields, hook(err)...)
+	}
+	errorHooksMu.RUnlock()
+	return fields
+}
+
+// ErrorCauses -- непосредственные причины ошибки: Unwrap() error или Unwrap() []error (errors.Join)
+func ErrorCauses(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+	return nil
+}
+
+// ErrorType -- имя типа ошибки, как в %T
+func ErrorType(err error) string {
+	if err == nil {
+		return "nil"
+	}
+	return reflect.TypeOf(err).String()
+}
+
+// FormatLogfmtError -- добавляет в буфер ошибку парами logfmt:
+// key="сообщение" key.type=*pkg.Error key.chain="*fmt.wrapError > [*errors.errorString | *fs.PathError > syscall.Errno]" key.field=..
+// Цепочка -- только если есть причины, сообщения причин обычно уже входят в сообщение обертки.
+func FormatLogfmtError(buf *[]byte, key string, err error) {
+	formatLogfmtError(buf, key, err, "")
+}
+
+func formatLogfmtError(buf *[]byte, key string, err error, keyColor string) {
+	formatLogfmtKey(buf, key, keyColor)
+	if err == nil {
+		*buf = append(*buf, "nil"...)
+		return
+	}
+	FormatLogfmtValue(buf, err.Error())
+	formatLogfmtKey(buf, key+".type", keyColor)
+	FormatLogfmtValue(buf, ErrorType(err))
+	if causes := ErrorCauses(err); len(causes) > 0 {
+		var chain []byte
+		formatErrorChain(&chain, causes, 0)
+		formatLogfmtKey(buf, key+".chain", keyColor)
+		FormatLogfmtValue(buf, string(chain))
+	}
+	for _, field := range ErrorFields(err) {
+		FormatColoredField(buf, keyColor, key+"."+field.Key, field.Value)
+	}
+}
+
+// formatErrorChain -- типы причин через " > ", несколько причин (errors.Join) -- в [] через " | "
+func formatErrorChain(buf *[]byte, causes []error, depth int) {
+	if depth >= maxErrorDepth {
+		*buf = append(*buf, "..."...)
+		return
+	}
+	if len(causes) > 1 {
+		*buf = append(*buf, '[')
+	}
+	for i, cause := range causes {
+		if i > 0 {
+			*buf = append(*buf, " | "...)
+		}
+		*buf = append(*buf, ErrorType(cause)...)
+		if next := ErrorCauses(cause); len(next) > 0 {
+			*buf = append(*buf, " > "...)
+			formatErrorChain(buf, next, depth+1)
+		}
+	}
+	if len(causes) > 1 {
+		*buf = append(*buf, ']')
+	}
+}
+
+// FormatJsonError -- добавляет в буфер ошибку объектом json:
+// {"message":"...","type":"*pkg.Error","causes":[{..},..],"field":..}
+func FormatJsonError(buf *[]byte, err error) {
+	formatJsonError(buf, err, 0)
+}
+
+func formatJsonError(buf *[]byte, err error, depth int) {
+	if err == nil {
+		*buf = append(*buf, "null"...)
+		return
+	}
+	*buf = append(*buf, `{"message":`...)
+	FormatJsonString(buf, err.Error())
+	*buf = append(*buf, `,"type":`...)
+	FormatJsonString(buf, ErrorType(err))
+	if causes := ErrorCauses(err); len(causes) > 0 && depth < maxErrorDepth {
+		*buf = append(*buf, `,"causes":[`...)
+		for i, cause := range causes {
+			if i > 0 {
+				*buf = append(*buf, ',')
+			}
+			formatJsonError(buf, cause, depth+1)
+		}
+		*buf = append(*buf, ']')
+	}
+	// у каждой ошибки -- собственные поля, поставщики обычно сами разворачивают цепочку -- только у верхней
+	var fields []Field
+	if fielder, ok := err.(ErrorFielder); ok {
+		fields = fielder.ErrorFields()
+	}
+	if depth == 0 {
+		fields = append(fields, errorHookFields(err)...)
+	}
+	for _, field := range fields {
+		*buf = append(*buf, ',')
+		FormatJsonString(buf, field.Key)
+		*buf = append(*buf, ':')
+		FormatJsonAny(buf, field.Value)
+	}
+	*buf = append(*buf, '}')
+}
@@ -2,9 +2,15 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -64,10 +70,73 @@ const (
 	EscWhiteCurrent     = "\x1b[1;37m"  // белым(ярким, жирным) по текущему
 )
 
+// Именованные форматы времени @see FormatTimestamp(). Прочие значения -- layout пакета time.
+const (
+	TimeFormatRFC3339     = "RFC3339"
+	TimeFormatRFC3339Nano = "RFC3339Nano"
+	TimeFormatUnix        = "unix"      // секунды от 1970-01-01 UTC
+	TimeFormatUnixMilli   = "unixmilli" // миллисекунды ..
+	TimeFormatUnixNano    = "unixnano"  // наносекунды ..
+	TimeFormatISOWeek     = "isoweek"   // неделя года по ISO8601: 2006-W01-1
+)
+
+// TimeFormats -- именованные layout пакета time, подставляются вместо имени @see TimeLayout()
+var TimeFormats = map[string]string{
+	TimeFormatRFC3339:     time.RFC3339,
+	TimeFormatRFC3339Nano: time.RFC3339Nano,
+	"RFC1123":             time.RFC1123,
+	"Kitchen":             time.Kitchen,
+	"Stamp":               time.Stamp,
+	"StampMilli":          time.StampMilli,
+	"StampMicro":          time.StampMicro,
+	"DateTime":            "2006-01-02 15:04:05",
+	"DateOnly":            "2006-01-02",
+	"TimeOnly":            "15:04:05",
+}
+
+// TimeLayout -- layout по имени из TimeFormats. Unix* и isoweek, как и свои layout, отдаются как есть.
+// Разрешается один раз при настройке, чтобы не искать в карте на каждую запись.
+func TimeLayout(format string) string {
+	if layout, ok := TimeFormats[format]; ok {
+		return layout
+	}
+	return format
+}
+
+// IsNumericTime -- время в этом формате -- число (в json без кавычек)
+func IsNumericTime(format string) bool {
+	return format == TimeFormatUnix || format == TimeFormatUnixMilli || format == TimeFormatUnixNano
+}
+
+// FormatTimestamp -- добавляет в буфер время в заданном формате: unix*, isoweek или layout (@see TimeLayout())
+func FormatTimestamp(buf *[]byte, now time.Time, format string) {
+	switch format {
+	case TimeFormatUnix:
+		*buf = strconv.AppendInt(*buf, now.Unix(), 10)
+	case TimeFormatUnixMilli:
+		*buf = strconv.AppendInt(*buf, now.UnixMilli(), 10)
+	case TimeFormatUnixNano:
+		*buf = strconv.AppendInt(*buf, now.UnixNano(), 10)
+	case TimeFormatISOWeek:
+		year, week := now.ISOWeek()
+		day := int(now.Weekday())
+		if day == 0 { // воскресенье -- 7-й день недели
+			day = 7
+		}
+		itoaBuf(buf, year, 4)
+		*buf = append(*buf, '-', 'W')
+		itoaBuf(buf, week, 2)
+		*buf = append(*buf, '-')
+		itoaBuf(buf, day, 1)
+	default:
+		*buf = now.AppendFormat(*buf, format)
+	}
+}
+
 // FormatTime -- добавляет в заданный буфер время в текущем формате лога
 func FormatTime(buf *[]byte, now time.Time, flags int) {
 	if flags&LogUTC != 0 {
-		//		Now = Now.UTC()
+		now = now.UTC()
 	}
 	if flags&LogDate != 0 {
 		y, m, d := now.Date()
@@ -89,13 +158,60 @@ func FormatTime(buf *[]byte, now time.Time, flags int) {
 
 		if flags&LogMicroSeconds != 0 {
 			*buf = append(*buf, '.')
-			itoaBuf(buf, now.Nanosecond()/1e3, 3)
+			itoaBuf(buf, now.Nanosecond()/1e3, 6)
 		}
 	}
+}
+
+// FormatTimeISO -- добавляет в буфер время в формате ISO8601 (RFC3339): yyyy-mm-ddThh:mm:ss.micro+hh:mm
+// Состав частей даты и времени определяется теми же флагами, что и в FormatTime()
+func FormatTimeISO(buf *[]byte, now time.Time, flags int) {
+	if flags&LogUTC != 0 {
+		now = now.UTC()
+	}
+	if flags&LogDate != 0 {
+		y, m, d := now.Date()
+		itoaBuf(buf, y, 4)
+		*buf = append(*buf, '-')
+		itoaBuf(buf, int(m), 2)
+		*buf = append(*buf, '-')
+		itoaBuf(buf, d, 2)
+		if flags&(LogTime|LogMicroSeconds) != 0 {
+			*buf = append(*buf, 'T')
+		}
+	}
+	if flags&(LogTime|LogMicroSeconds) != 0 {
+		h, m, s := now.Clock()
+		itoaBuf(buf, h, 2)
+		*buf = append(*buf, ':')
+		itoaBuf(buf, m, 2)
+		*buf = append(*buf, ':')
+		itoaBuf(buf, s, 2)
+
+		if flags&LogMicroSeconds != 0 {
+			*buf = append(*buf, '.')
+			itoaBuf(buf, now.Nanosecond()/1e3, 6)
+		}
 
+		_, offset := now.Zone()
+		if offset == 0 {
+			*buf = append(*buf, 'Z')
+			return
+		}
+		if offset < 0 {
+			*buf = append(*buf, '-')
+			offset = -offset
+		} else {
+			*buf = append(*buf, '+')
+		}
+		itoaBuf(buf, offset/3600, 2)
+		*buf = append(*buf, ':')
+		itoaBuf(buf, offset%3600/60, 2)
+	}
 }
 
 // FormatFileLine -- добавляет в буфер информацию о файле и номере строки
+// depth -- как у runtime.Caller(): 0 -- сама FormatFileLine(), 1 -- вызвавшая ее и т.д.
 func FormatFileLine(buf *[]byte, depth int, isShort bool) {
 	var (
 		ok   bool
@@ -114,7 +230,28 @@ func FormatFileLine(buf *[]byte, depth int, isShort bool) {
 	itoaBuf(buf, line, 4) // max 9999 line number!
 }
 
+// FormatCaller -- добавляет в буфер файл и номер строки в общепринятом виде file.go:123
+// depth -- глубина вызова от функции, вызвавшей FormatCaller()
+func FormatCaller(buf *[]byte, depth int, isShort bool) {
+	var (
+		ok   bool
+		line int
+		file string
+	)
+	if _, file, line, ok = runtime.Caller(depth + 1); !ok {
+		file = "???"
+		line = 0
+	}
+	if isShort {
+		file = filepath.Base(file)
+	}
+	*buf = append(*buf, file...)
+	*buf = append(*buf, ':')
+	itoaBuf(buf, line, -1)
+}
+
 // FormatFuncLine -- добавляет в буфер название функции(метода) и номер строки файла
+// depth -- как у GetCaller(): 1 -- сама FormatFuncLine(), 2 -- вызвавшая ее и т.д.
 func FormatFuncLine(buf *[]byte, depth int) {
 	var frame runtime.Frame
 	var ok bool
@@ -152,14 +289,349 @@ func FormatSlice(buf *[]byte, messages []string) {
 	}
 }
 
+// LevelName -- имя уровня в нижнем регистре для logfmt: "INFO " -> "info". Свои уровни -- как есть, без пробелов
+func LevelName(level string) string {
+	switch level {
+	case LogPanicPrefix:
+		return "panic"
+	case LogFatalPrefix:
+		return "fatal"
+	case LogErrorPrefix:
+		return "error"
+	case LogWarnPrefix:
+		return "warn"
+	case LogInfoPrefix:
+		return "info"
+	case LogDebugPrefix:
+		return "debug"
+	}
+	return strings.TrimSpace(level)
+}
+
+// ContinuationIndent -- отступ строк продолжения многострочного сообщения @see FormatEscaped()
+const ContinuationIndent = "\n    "
+
+// FormatEscaped -- добавляет в буфер текст сообщения, безопасный для терминала и разбора лога:
+// переводы строк -- строками продолжения с отступом, прочие управляющие символы (ESC, CR, C1..) и
+// невалидный UTF-8 -- видимыми \xNN или \u00NN. Табуляция -- как есть.
+func FormatEscaped(buf *[]byte, message string) {
+	for i := 0; i < len(message); {
+		c := message[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(message[i:])
+			switch {
+			case r == utf8.RuneError && size == 1:
+				*buf = append(*buf, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+			case r >= 0x80 && r <= 0x9f: // C1: CSI и прочие управляющие
+				*buf = append(*buf, '\\', 'u', '0', '0', hexDigits[r>>4], hexDigits[r&0xf])
+			default:
+				*buf = append(*buf, message[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+		switch {
+		case c == '\n':
+			*buf = append(*buf, ContinuationIndent...)
+		case c == '\r':
+			*buf = append(*buf, '\\', 'r')
+		case c == '\t':
+			*buf = append(*buf, c)
+		case c < ' ' || c == 0x7f:
+			*buf = append(*buf, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			*buf = append(*buf, c)
+		}
+		i++
+	}
+}
+
+// FormatLogfmtValue -- добавляет в буфер значение для logfmt. Берет в кавычки, если значение пустое
+// или содержит пробелы, '=', '"' и управляющие символы; внутри кавычек экранирует '"', '\' и управляющие символы.
+func FormatLogfmtValue(buf *[]byte, value string) {
+	if !needsLogfmtQuote(value) {
+		*buf = append(*buf, value...)
+		return
+	}
+	*buf = append(*buf, '"')
+	appendLogfmtEscaped(buf, value)
+	*buf = append(*buf, '"')
+}
+
+// appendLogfmtEscaped -- добавляет в буфер строку с экранированием для значения logfmt в кавычках
+func appendLogfmtEscaped(buf *[]byte, value string) {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			*buf = append(*buf, '\\', c)
+		case c == '\n':
+			*buf = append(*buf, '\\', 'n')
+		case c == '\r':
+			*buf = append(*buf, '\\', 'r')
+		case c == '\t':
+			*buf = append(*buf, '\\', 't')
+		case c < ' ' || c == 0x7f:
+			*buf = append(*buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			*buf = append(*buf, c)
+		}
+	}
+}
+
+// FormatLogfmtPair -- добавляет в буфер пару key=value через пробел. Недопустимые символы ключа пропускаются
+func FormatLogfmtPair(buf *[]byte, key, value string) {
+	formatLogfmtKey(buf, key, "")
+	FormatLogfmtValue(buf, value)
+}
+
+// formatLogfmtKey -- добавляет в буфер " key=", ключ -- раскрашенным, если задан цвет
+func formatLogfmtKey(buf *[]byte, key, color string) {
+	*buf = append(*buf, ' ')
+	*buf = append(*buf, color...)
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c > ' ' && c != '=' && c != '"' && c != 0x7f {
+			*buf = append(*buf, c)
+		}
+	}
+	if color != "" {
+		*buf = append(*buf, EscStart+EscReset+EscColorEnd...)
+	}
+	*buf = append(*buf, '=')
+}
+
+// FormatLogfmtField -- добавляет в буфер поле key=value через пробел, значение любого типа @see FormatAny()
+// Ошибки -- с типом и цепочкой причин @see FormatLogfmtError()
+func FormatLogfmtField(buf *[]byte, key string, value any) {
+	FormatColoredField(buf, "", key, value)
+}
+
+// FormatColoredField -- то же, что FormatLogfmtField() с раскраской ключа заданным цветом
+func FormatColoredField(buf *[]byte, keyColor, key string, value any) {
+	switch v := value.(type) {
+	case string:
+		formatLogfmtKey(buf, key, keyColor)
+		FormatLogfmtValue(buf, v)
+	case error:
+		formatLogfmtError(buf, key, v, keyColor)
+	default:
+		formatLogfmtKey(buf, key, keyColor)
+		FormatAny(buf, value)
+	}
+}
+
+// FormatAny -- добавляет в буфер значение поля: числа и bool без аллокаций, строки -- в кавычках logfmt по необходимости
+func FormatAny(buf *[]byte, value any) {
+	switch v := value.(type) {
+	case string:
+		FormatLogfmtValue(buf, v)
+	case int:
+		*buf = strconv.AppendInt(*buf, int64(v), 10)
+	case int32:
+		*buf = strconv.AppendInt(*buf, int64(v), 10)
+	case int64:
+		*buf = strconv.AppendInt(*buf, v, 10)
+	case uint:
+		*buf = strconv.AppendUint(*buf, uint64(v), 10)
+	case uint32:
+		*buf = strconv.AppendUint(*buf, uint64(v), 10)
+	case uint64:
+		*buf = strconv.AppendUint(*buf, v, 10)
+	case float32:
+		*buf = strconv.AppendFloat(*buf, float64(v), 'g', -1, 32)
+	case float64:
+		*buf = strconv.AppendFloat(*buf, v, 'g', -1, 64)
+	case bool:
+		*buf = strconv.AppendBool(*buf, v)
+	case time.Duration:
+		FormatLogfmtValue(buf, v.String())
+	case error:
+		FormatLogfmtValue(buf, v.Error())
+	case fmt.Stringer:
+		FormatLogfmtValue(buf, v.String())
+	case nil:
+		*buf = append(*buf, "nil"...)
+	default:
+		FormatLogfmtValue(buf, fmt.Sprint(v))
+	}
+}
+
+// FormatJsonString -- добавляет в буфер строку json в кавычках с экранированием. Невалидный UTF-8 заменяется на \ufffd
+func FormatJsonString(buf *[]byte, str string) {
+	*buf = append(*buf, '"')
+	for i := 0; i < len(str); {
+		c := str[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(str[i:])
+			if r == utf8.RuneError && size == 1 {
+				*buf = append(*buf, `\ufffd`...)
+			} else {
+				*buf = append(*buf, str[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+		switch {
+		case c == '"' || c == '\\':
+			*buf = append(*buf, '\\', c)
+		case c == '\n':
+			*buf = append(*buf, '\\', 'n')
+		case c == '\r':
+			*buf = append(*buf, '\\', 'r')
+		case c == '\t':
+			*buf = append(*buf, '\\', 't')
+		case c < ' ':
+			*buf = append(*buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			*buf = append(*buf, c)
+		}
+		i++
+	}
+	*buf = append(*buf, '"')
+}
+
+// FormatJsonAny -- добавляет в буфер значение поля в json: числа и bool без аллокаций, прочее через json.Marshal()
+// Ошибки -- объектом с причинами @see FormatJsonError()
+func FormatJsonAny(buf *[]byte, value any) {
+	switch v := value.(type) {
+	case string:
+		FormatJsonString(buf, v)
+	case int:
+		*buf = strconv.AppendInt(*buf, int64(v), 10)
+	case int32:
+		*buf = strconv.AppendInt(*buf, int64(v), 10)
+	case int64:
+		*buf = strconv.AppendInt(*buf, v, 10)
+	case uint:
+		*buf = strconv.AppendUint(*buf, uint64(v), 10)
+	case uint32:
+		*buf = strconv.AppendUint(*buf, uint64(v), 10)
+	case uint64:
+		*buf = strconv.AppendUint(*buf, v, 10)
+	case float32:
+		*buf = strconv.AppendFloat(*buf, float64(v), 'g', -1, 32)
+	case float64:
+		*buf = strconv.AppendFloat(*buf, v, 'g', -1, 64)
+	case bool:
+		*buf = strconv.AppendBool(*buf, v)
+	case time.Duration:
+		FormatJsonString(buf, v.String())
+	case error:
+		FormatJsonError(buf, v)
+	case fmt.Stringer:
+		FormatJsonString(buf, v.String())
+	case nil:
+		*buf = append(*buf, "null"...)
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			*buf = append(*buf, data...)
+		} else {
+			FormatJsonString(buf, fmt.Sprint(v))
+		}
+	}
+}
+
+// quoteLogfmtTail -- берет в кавычки значение, уже добавленное в буфер с позиции start, если в нем есть пробелы или '='
+func quoteLogfmtTail(buf *[]byte, start int) {
+	for _, c := range (*buf)[start:] {
+		if c == ' ' || c == '=' {
+			*buf = append(*buf, 0)
+			copy((*buf)[start+1:], (*buf)[start:len(*buf)-1])
+			(*buf)[start] = '"'
+			*buf = append(*buf, '"')
+			return
+		}
+	}
+}
+
+const hexDigits = "0123456789abcdef"
+
+// needsLogfmtQuote -- надо ли брать значение в кавычки?
+func needsLogfmtQuote(value string) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// loggerPkgPrefix -- начало имен функций самого пакета логгера, их кадры в стек записи не попадают
+var loggerPkgPrefix = reflect.TypeOf((*BaseLogger)(nil)).Elem().PkgPath() + "."
+
+// forStack -- обход кадров стека вызовов без кадров самого пакета логгера
+func forStack(stack []uintptr, do func(frame *runtime.Frame)) {
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, loggerPkgPrefix) {
+			do(&frame)
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// FormatStack -- добавляет в буфер стек вызовов блоком с отступами, как в панике:
+// \tpackage.Function\n\t\t/path/file.go:12\n
+func FormatStack(buf *[]byte, stack []uintptr) {
+	forStack(stack, func(frame *runtime.Frame) {
+		*buf = append(*buf, '\t')
+		*buf = append(*buf, frame.Function...)
+		*buf = append(*buf, '\n', '\t', '\t')
+		*buf = append(*buf, frame.File...)
+		*buf = append(*buf, ':')
+		itoaBuf(buf, frame.Line, -1)
+		*buf = append(*buf, '\n')
+	})
+}
+
+// FormatLogfmtStack -- добавляет в буфер стек вызовов одним значением logfmt: "package.Function file.go:12\n.."
+func FormatLogfmtStack(buf *[]byte, stack []uintptr) {
+	*buf = append(*buf, '"')
+	sep := ""
+	forStack(stack, func(frame *runtime.Frame) {
+		*buf = append(*buf, sep...)
+		appendLogfmtEscaped(buf, frame.Function)
+		*buf = append(*buf, ' ')
+		appendLogfmtEscaped(buf, frame.File)
+		*buf = append(*buf, ':')
+		itoaBuf(buf, frame.Line, -1)
+		sep = `\n`
+	})
+	*buf = append(*buf, '"')
+}
+
+// FormatJsonStack -- добавляет в буфер стек вызовов массивом json: [{"func":"...","file":"...","line":12},..]
+func FormatJsonStack(buf *[]byte, stack []uintptr) {
+	*buf = append(*buf, '[')
+	sep := ""
+	forStack(stack, func(frame *runtime.Frame) {
+		*buf = append(*buf, sep...)
+		*buf = append(*buf, `{"func":`...)
+		FormatJsonString(buf, frame.Function)
+		*buf = append(*buf, `,"file":`...)
+		FormatJsonString(buf, frame.File)
+		*buf = append(*buf, `,"line":`...)
+		itoaBuf(buf, frame.Line, -1)
+		*buf = append(*buf, '}')
+		sep = ","
+	})
+	*buf = append(*buf, ']')
+}
+
 // GlDefColors -- дефолтная таблица цветов для вывода сообщения
 var GlDefColors = map[string]string{
-	LogPanicPrefix: EscStart + EscBlinkFast + EscColorEnd + EscStart + EscBold + ";" + EscRedCurrent + EscColorEnd,
-	LogFatalPrefix: EscStart + EscBlinkSlow + EscColorEnd + EscStart + EscBold + ";" + EscRedCurrent + EscColorEnd,
-	LogErrorPrefix: EscStart + EscBold + ";" + EscRedCurrent + EscColorEnd,
-	LogWarnPrefix:  EscStart + EscNormalFont + ";" + EscMagentaCurrent + EscColorEnd,
-	LogInfoPrefix:  EscStart + EscNormalFont + ";" + EscGreenCurrent + EscColorEnd,
-	LogDebugPrefix: EscStart + EscCoursive + ";" + EscBlueCurrent + EscColorEnd,
+	LogPanicPrefix: EscStart + EscBlinkFast + ";" + EscBold + EscColorEnd + EscRedCurrent,
+	LogFatalPrefix: EscStart + EscBlinkSlow + ";" + EscBold + EscColorEnd + EscRedCurrent,
+	LogErrorPrefix: EscStart + EscBold + EscColorEnd + EscRedCurrent,
+	LogWarnPrefix:  EscMagentaCurrent,
+	LogInfoPrefix:  EscGreenCurrent,
+	LogDebugPrefix: EscStart + EscCoursive + EscColorEnd + EscBlueCurrent,
 }
 
 // FormatColored -- раскраска строки заданным цветом с последующей отменой
@@ -0,0 +1,324 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Шаблон строки лога. Компилируется один раз в Init() в список добавлялок @see LogAppender
+// Синтаксис элемента: %[-][width][.max]{name[:arg]}, например:
+//
+//	%{time:RFC3339} [%-5{level}] %-20.20{caller} %{trace} %{msg} %{fields}
+//
+// "-" -- выравнивание влево (дополнение пробелами справа), width -- минимальная ширина, .max -- обрезка до max символов.
+// Всё остальное выводится как есть, "%%" -- сам символ процента.
+//
+// Элементы:
+//
+//	time   -- время по BaseLogger.TimeFormat или флагам (FormatTime), или в формате arg @see FormatTimestamp()
+//	level  -- уровень, с раскраской по флагу LogLevelColored и теме логгера @see ColorTheme
+//	caller -- файл#строка (FormatFileLine), длинное имя файла только по флагу LogLongFile
+//	func   -- функция#строка (FormatFuncLine)
+//	trace  -- значение поля с ключом BaseLogger.TraceKey: из полей записи, иначе из полей логгера
+//	name   -- имя логгера в иерархии @see Named()
+//	msg    -- текст сообщения
+//	fields -- поля логгера и записи как key=value через пробел, кроме трассировки
+const (
+	LayoutTime   = "time"
+	LayoutLevel  = "level"
+	LayoutCaller = "caller"
+	LayoutFunc   = "func"
+	LayoutTrace  = "trace"
+	LayoutName   = "name"
+	LayoutMsg    = "msg"
+	LayoutFields = "fields"
+)
+
+// CompileLayout -- разбор шаблона в список добавлялок. Ошибка -- с позицией в шаблоне.
+func CompileLayout(pattern string) ([]LogAppender, error) {
+	var appenders []LogAppender
+	start := 0
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			continue
+		}
+		if i+1 < len(pattern) && pattern[i+1] == '%' {
+			appenders = appendLiteral(appenders, pattern[start:i+1])
+			i++
+			start = i + 1
+			continue
+		}
+		appenders = appendLiteral(appenders, pattern[start:i])
+
+		pos := i
+		i++
+		isLeft := false
+		if i < len(pattern) && pattern[i] == '-' {
+			isLeft = true
+			i++
+		}
+		width, n := layoutNumber(pattern[i:])
+		i += n
+		maxWidth := 0
+		if i < len(pattern) && pattern[i] == '.' {
+			i++
+			if maxWidth, n = layoutNumber(pattern[i:]); n == 0 {
+				return nil, fmt.Errorf("CompileLayout(): position %d: no max width after '.'", pos)
+			}
+			i += n
+		}
+		if i >= len(pattern) || pattern[i] != '{' {
+			return nil, fmt.Errorf("CompileLayout(): position %d: '{' expected after '%%'", pos)
+		}
+		end := i + 1
+		for end < len(pattern) && pattern[end] != '}' {
+			end++
+		}
+		if end >= len(pattern) {
+			return nil, fmt.Errorf("CompileLayout(): position %d: '}' not found", pos)
+		}
+
+		appender, err := layoutElement(pattern[i+1 : end])
+		if err != nil {
+			return nil, fmt.Errorf("CompileLayout(): position %d: %s", pos, err.Error())
+		}
+		if width > 0 || maxWidth > 0 {
+			appender = layoutPadded(appender, width, maxWidth, isLeft)
+		}
+		appenders = append(appenders, appender)
+		i = end
+		start = end + 1
+	}
+	appenders = appendLiteral(appenders, pattern[start:])
+
+	return appenders, nil
+}
+
+// FormatLayout -- строчный режим вывода по скомпилированному шаблону BaseLogger.Layout
+func (baselog *BaseLogger) FormatLayout(buf *[]byte, rec *LogRecord) {
+	rec.Depth++ // вызывающий для LogAppender -- FormatLayout()
+	for _, appender := range baselog.formatter().Layout {
+		appender(buf, baselog, rec)
+	}
+	rec.Depth--
+	if len(*buf) == 0 || (*buf)[len(*buf)-1] != '\n' {
+		*buf = append(*buf, '\n')
+	}
+	if rec.Stack != nil {
+		FormatStack(buf, rec.Stack)
+	}
+}
+
+// layoutElement -- добавлялка для одного элемента шаблона {name[:arg]}
+func layoutElement(element string) (LogAppender, error) {
+	name, arg := element, ""
+	for i := 0; i < len(element); i++ {
+		if element[i] == ':' {
+			name, arg = element[:i], element[i+1:]
+			break
+		}
+	}
+
+	switch name {
+	case LayoutTime:
+		if arg == "" {
+			return appendTime, nil
+		}
+		format := TimeLayout(arg)
+		return func(buf *[]byte, _ *BaseLogger, rec *LogRecord) {
+			FormatTimestamp(buf, rec.Now, format)
+		}, nil
+	case LayoutLevel:
+		return appendLevel, nil
+	case LayoutCaller:
+		return appendCaller, nil
+	case LayoutFunc:
+		return appendFunc, nil
+	case LayoutTrace:
+		return appendTrace, nil
+	case LayoutName:
+		return appendName, nil
+	case LayoutMsg:
+		return appendMessage, nil
+	case LayoutFields:
+		return appendFields, nil
+	}
+	return nil, fmt.Errorf("unknown element '%s'", name)
+}
+
+func appendLiteral(appenders []LogAppender, literal string) []LogAppender {
+	if literal == "" {
+		return appenders
+	}
+	return append(appenders, func(buf *[]byte, _ *BaseLogger, _ *LogRecord) {
+		*buf = append(*buf, literal...)
+	})
+}
+
+// appendTime -- время в формате логгера или по его флагам без ведущего пробела FormatTime()
+func appendTime(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	color := formatColorStart(buf, baselog.Colors().TimeColor())
+	if timeFormat := baselog.formatter().TimeFormat; timeFormat != "" {
+		FormatTimestamp(buf, rec.Now, timeFormat)
+	} else {
+		start := len(*buf)
+		FormatTime(buf, rec.Now, baselog.GetFlags())
+		if len(*buf) > start && (*buf)[start] == ' ' {
+			*buf = append((*buf)[:start], (*buf)[start+1:]...)
+		}
+	}
+	formatColorEnd(buf, color)
+}
+
+func appendLevel(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	if color := baselog.Colors().LevelColor(rec.Level); color != "" {
+		FormatColored(buf, color, rec.Level)
+	} else {
+		*buf = append(*buf, rec.Level...)
+	}
+}
+
+// appendCaller -- +2: сама appendCaller() и FormatFileLine()
+func appendCaller(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	color := formatColorStart(buf, baselog.Colors().CallerColor())
+	FormatFileLine(buf, rec.Depth+2, baselog.GetFlags()&LogLongFile == 0)
+	formatColorEnd(buf, color)
+}
+
+// appendFunc -- +3: сама appendFunc(), FormatFuncLine() и GetCaller()
+func appendFunc(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	color := formatColorStart(buf, baselog.Colors().CallerColor())
+	FormatFuncLine(buf, rec.Depth+3)
+	formatColorEnd(buf, color)
+}
+
+// appendTrace -- значение трассировки из полей записи, иначе из полей логгера
+func appendTrace(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	traceKey := baselog.GetTraceKey()
+	for _, fields := range [2][]Field{rec.Fields, baselog.GetFields()} {
+		for _, field := range fields {
+			if field.Key == traceKey {
+				FormatAny(buf, field.Value)
+				return
+			}
+		}
+	}
+}
+
+func appendName(buf *[]byte, baselog *BaseLogger, _ *LogRecord) {
+	*buf = append(*buf, baselog.Name...)
+}
+
+func appendMessage(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	baselog.formatMessage(buf, rec.Message)
+}
+
+func appendFields(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+	start := len(*buf)
+	keyColor, traceKey := baselog.Colors().KeyColor(), baselog.GetTraceKey()
+	for _, field := range baselog.GetFields() {
+		if field.Key != traceKey {
+			FormatColoredField(buf, keyColor, field.Key, field.Value)
+		}
+	}
+	for _, field := range rec.Fields {
+		if field.Key != traceKey {
+			FormatColoredField(buf, keyColor, field.Key, field.Value)
+		}
+	}
+	if len(*buf) > start { // без первого пробела
+		*buf = append((*buf)[:start], (*buf)[start+1:]...)
+	}
+}
+
+// layoutPadded -- обертка добавлялки выравниванием и обрезкой по ширине в символах (ESC-последовательности не считаются)
+func layoutPadded(appender LogAppender, width, maxWidth int, isLeft bool) LogAppender {
+	return func(buf *[]byte, baselog *BaseLogger, rec *LogRecord) {
+		start := len(*buf)
+		rec.Depth++
+		appender(buf, baselog, rec)
+		rec.Depth--
+
+		size := visibleLen((*buf)[start:])
+		if maxWidth > 0 && size > maxWidth {
+			*buf = (*buf)[:start+visibleCut((*buf)[start:], maxWidth)]
+			size = maxWidth
+		}
+		if size >= width {
+			return
+		}
+		end := len(*buf)
+		for ; size < width; size++ {
+			*buf = append(*buf, ' ')
+		}
+		if !isLeft { // сдвиг вправо, пробелы в начало
+			pad := len(*buf) - end
+			copy((*buf)[start+pad:], (*buf)[start:end])
+			for i := start; i < start+pad; i++ {
+				(*buf)[i] = ' '
+			}
+		}
+	}
+}
+
+// layoutNumber -- целое без знака в начале строки и его длина
+func layoutNumber(str string) (int, int) {
+	n := 0
+	for n < len(str) && str[n] >= '0' && str[n] <= '9' {
+		n++
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	val, _ := strconv.Atoi(str[:n])
+	return val, n
+}
+
+// visibleLen -- число видимых символов строки без ESC-последовательностей раскраски
+func visibleLen(text []byte) int {
+	size := 0
+	for i := 0; i < len(text); {
+		if n := escLen(text[i:]); n > 0 {
+			i += n
+			continue
+		}
+		_, n := utf8.DecodeRune(text[i:])
+		i += n
+		size++
+	}
+	return size
+}
+
+// visibleCut -- длина в байтах начала строки, содержащего maxWidth видимых символов
+func visibleCut(text []byte, maxWidth int) int {
+	size := 0
+	i := 0
+	for i < len(text) {
+		if n := escLen(text[i:]); n > 0 {
+			i += n
+			continue
+		}
+		if size == maxWidth {
+			break
+		}
+		_, n := utf8.DecodeRune(text[i:])
+		i += n
+		size++
+	}
+	return i
+}
+
+// escLen -- длина ESC-последовательности раскраски "\x1b[...m" в начале текста или 0
+func escLen(text []byte) int {
+	if len(text) < 2 || text[0] != 0x1b || text[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(text); i++ {
+		if text[i] == 'm' {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -18,21 +18,40 @@ type JsonMessage struct {
 	Message  string `json:"message"`
 }
 
-// BaseLogMarshal -- местный маршаллер в JSON лога с типовыми параметрами
+// DefJsonTimeFormat -- формат времени json по умолчанию
+const DefJsonTimeFormat = "2006-01-02 15:04:05.000"
+
+// BaseLogMarshal -- местный маршаллер в JSON лога с типовыми параметрами @see JsonMessage
+// Добавляет в буфер без reflect и аллокаций. Для своих форматов времени и полей @see BaseLogger.FormatJson()
 func BaseLogMarshal(buf *[]byte, depth int, now time.Time, level, message string) error {
-	frame, _ := GetCaller(depth + 1)
+	formatJson(buf, depth+1, now, level, message, DefJsonTimeFormat)
+	*buf = append(*buf, '}')
+	return nil
+}
 
-	mess := JsonMessage{
-		Level:    level,
-		DateTime: now.Format("2006-01-02 15:04:05.000"),
-		FuncName: filepath.Base(frame.Function),
-		FileName: frame.File,
-		LineNum:  frame.Line,
-		Message:  message,
+// formatJson -- общая часть json записи без закрывающей скобки, чтобы можно было дописать поля
+// depth -- от вызвавшего formatJson(), +2: сама formatJson() и GetCaller()
+func formatJson(buf *[]byte, depth int, now time.Time, level, message, timeFormat string) {
+	frame, _ := GetCaller(depth + 2)
+
+	*buf = append(*buf, `{"Level":`...)
+	FormatJsonString(buf, level)
+	*buf = append(*buf, `,"date_time":`...)
+	if IsNumericTime(timeFormat) {
+		FormatTimestamp(buf, now, timeFormat)
+	} else {
+		*buf = append(*buf, '"')
+		FormatTimestamp(buf, now, timeFormat)
+		*buf = append(*buf, '"')
 	}
-	var err error
-	*buf, err = json.Marshal(mess)
-	return err
+	*buf = append(*buf, `,"func_name":`...)
+	FormatJsonString(buf, filepath.Base(frame.Function))
+	*buf = append(*buf, `,"file_name":`...)
+	FormatJsonString(buf, frame.File)
+	*buf = append(*buf, `,"line_num":`...)
+	*buf = strconv.AppendInt(*buf, int64(frame.Line), 10)
+	*buf = append(*buf, `,"message":`...)
+	FormatJsonString(buf, message)
 }
 
 // MapLogMarshal -- местный маршаллер в JSON лога с типовыми параметрами
@@ -53,20 +72,31 @@ func MapLogMarshal(buf *[]byte, depth int, now time.Time, level, message string,
 	return json.Marshal(mess)
 }
 
-// GetCallers -- отдает стек вызвавших логирование контекстов
+// GetCallers -- отдает стек вызвавших логирование контекстов, только ближайший кадр, или nil
 func GetCallers(skip int) *runtime.Frames {
-	rpc := make([]uintptr, 1)
-	n := runtime.Callers(skip+1, rpc[:])
-	if n < 1 {
+	pcs := GetCallerPCs(skip+1, make([]uintptr, 1))
+	if pcs == nil {
 		return nil
 	}
+	return runtime.CallersFrames(pcs)
+}
 
-	return runtime.CallersFrames(rpc)
+// GetCallerPCs -- заполняет буфер pcs адресами стека вызовов, отдает заполненную часть или nil.
+// skip=0 -- сама GetCallerPCs(), 1 -- вызвавший ее и т.д. Глубина стека -- не более len(pcs), разбор -- runtime.CallersFrames()
+func GetCallerPCs(skip int, pcs []uintptr) []uintptr {
+	n := runtime.Callers(skip+1, pcs)
+	if n < 1 {
+		return nil
+	}
+	return pcs[:n]
 }
 
 // GetCaller -- отдает собственно контекст, вызвавший логирование
 func GetCaller(skip int) (runtime.Frame, bool) {
-	frames := GetCallers(skip + 1)
-
-	return frames.Next()
+	pcs := GetCallerPCs(skip+1, make([]uintptr, 1))
+	if pcs == nil {
+		return runtime.Frame{}, false
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next() // второе значение -- "есть ли еще", а не успешность
+	return frame, frame.PC != 0
 }
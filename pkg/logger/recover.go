@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Что делать после логирования перехваченной паники @see Recover()
+const (
+	RecoverSwallow = iota // продолжить работу, паника поглощена
+	RecoverRepanic        // паниковать дальше тем же значением
+	RecoverExit           // завершить процесс с кодом возврата
+)
+
+// PanicKey, PanicTypeKey -- ключи полей перехваченной паники: значение и его тип
+const (
+	PanicKey     = "panic"
+	PanicTypeKey = "panic_type"
+)
+
+// RecoverOption -- настройка поведения Recover()
+type RecoverOption func(opts *recoverOptions)
+
+type recoverOptions struct {
+	mode     int
+	exitCode int
+	message  string
+}
+
+// WithRecoverMode -- что делать после логирования: RecoverSwallow (по умолчанию), RecoverRepanic, RecoverExit
+func WithRecoverMode(mode int) RecoverOption {
+	return func(opts *recoverOptions) { opts.mode = mode }
+}
+
+// WithRecoverExit -- завершать процесс с заданным кодом возврата
+func WithRecoverExit(code int) RecoverOption {
+	return func(opts *recoverOptions) {
+		opts.mode = RecoverExit
+		opts.exitCode = code
+	}
+}
+
+// WithRecoverMessage -- свой текст записи вместо "recovered panic"
+func WithRecoverMessage(message string) RecoverOption {
+	return func(opts *recoverOptions) { opts.message = message }
+}
+
+// Recover -- перехват паники в точке входа горутины, вызывается только так: defer lgr.Recover(opts...)
+// Пишет запись уровня PANIC со значением паники, его типом и стеком вызовов (независимо от StackLevel),
+// далее по настройке: поглощает панику, паникует дальше или завершает процесс. Перед двумя последними
+// выполняет завершители @see OnExit(); при поглощении процесс продолжает работу и завершители остаются на месте.
+func (baselog *BaseLogger) Recover(opts ...RecoverOption) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	options := recoverOptions{mode: RecoverSwallow, exitCode: 1, message: "recovered panic"}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if baselog.GetLevel() >= LogPanicLevel {
+		valueField := Field{Key: PanicKey, Value: value}
+		if err, ok := value.(error); ok {
+			valueField = Err(err)
+		}
+		fields := []Field{valueField, {Key: PanicTypeKey, Value: fmt.Sprintf("%T", value)}}
+		baselog.outlog(1, time.Now(), LogPanicPrefix, options.message, fields, true)
+	}
+	switch options.mode {
+	case RecoverRepanic:
+		baselog.RunExitHooks()
+		panic(value)
+	case RecoverExit:
+		baselog.RunExitHooks()
+		os.Exit(options.exitCode)
+	}
+}
+
+// OnExit -- добавить завершитель, выполняемый перед завершением процесса (Fatal, Panic, Recover с выходом или паникой дальше)
+// Завершители выполняются в обратном порядке, как defer.
+func (baselog *BaseLogger) OnExit(hook func()) {
+	if root := baselog.root.Load(); root != nil {
+		root.OnExit(hook)
+		return
+	}
+	baselog.Mu.Lock()
+	baselog.ExitHooks = append(baselog.ExitHooks, hook)
+	baselog.Mu.Unlock()
+}
+
+// RunExitHooks -- выполнить завершители в обратном порядке. Каждый выполняется один раз.
+func (baselog *BaseLogger) RunExitHooks() {
+	if root := baselog.root.Load(); root != nil {
+		root.RunExitHooks()
+		return
+	}
+	baselog.Mu.Lock()
+	hooks := baselog.ExitHooks
+	baselog.ExitHooks = nil
+	baselog.Mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+}
@@ -0,0 +1,327 @@
+package logger
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry -- иерархия именованных логгеров: "db.pool" наследует уровень, выводы и поля от "db", тот -- от корня.
+// Любому узлу можно задать свой уровень, вывод и поля, изменения сразу применяются ко всем потомкам.
+// Логгеры узлов живут все время работы, изменяются на месте -- их можно хранить. Уровень, вывод и поля
+// меняются на ходу без гонок с записью через эти логгеры. Порожденные от них через With() изменений уровня не получают.
+// Изменения настроек самого корня (SetLevel(), SetFlags(), Reload()..) тоже доходят до узлов, формат -- от корня.
+// Настройки логгеров узлов менять через Registry: свои изменения узла заменяются при следующем обновлении.
+type Registry struct {
+	mu   sync.RWMutex
+	root *registryNode
+}
+
+// LoggerInfo -- сведения об узле иерархии @see Registry.Walk()
+type LoggerInfo struct {
+	// Name -- полное имя, "" -- корень
+	Name string
+	// Level -- действующий уровень узла
+	Level int
+	// IsLevelSet -- уровень задан самому узлу, а не унаследован
+	IsLevelSet bool
+	Logger     *BaseLogger
+}
+
+type registryNode struct {
+	name     string
+	parent   *registryNode
+	children map[string]*registryNode
+	logger   *BaseLogger
+
+	// собственные настройки узла, иначе -- от родителя
+	level    int
+	hasLevel bool
+	out      io.Writer
+	hasOut   bool
+	fields   []Field
+}
+
+// NewRegistry -- иерархия логгеров от заданного корневого. Логгер -- корень только одной иерархии.
+func NewRegistry(root *BaseLogger) *Registry {
+	reg := &Registry{root: &registryNode{logger: root}}
+	reg.watch(root)
+	return reg
+}
+
+// Root -- корневой логгер иерархии
+func (reg *Registry) Root() *BaseLogger {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.root.logger
+}
+
+// SetRoot -- замена корневого логгера, логгеры всех узлов перенастраиваются от нового корня, включая формат.
+// Можно и во время записи через логгеры узлов: каждая запись выводится целиком по прежнему или по новому корню.
+func (reg *Registry) SetRoot(root *BaseLogger) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if old := reg.root.logger; old != root {
+		old.onChange.Store(nil)
+	}
+	reg.root.logger = root
+	reg.watch(root)
+	for _, child := range reg.root.children {
+		reg.update(child)
+	}
+}
+
+// watch -- изменения настроек корня (SetLevel(), Reload()..) применяются к логгерам узлов
+func (reg *Registry) watch(root *BaseLogger) {
+	onChange := func() {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+		if reg.root.logger != root {
+			return
+		}
+		for _, child := range reg.root.children {
+			reg.update(child)
+		}
+	}
+	root.onChange.Store(&onChange)
+}
+
+// Named -- логгер узла по имени через точку: "db.pool". Недостающие узлы создаются, "" -- корень.
+func (reg *Registry) Named(name string) *BaseLogger {
+	name = normalizeName(name)
+	reg.mu.RLock()
+	node := reg.find(name)
+	reg.mu.RUnlock()
+	if node != nil {
+		return node.logger
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	node = reg.root
+	for _, part := range strings.Split(name, ".") {
+		child, ok := node.children[part]
+		if !ok {
+			child = &registryNode{name: part, parent: node, logger: &BaseLogger{}}
+			if node != reg.root {
+				child.name = node.name + "." + part
+			}
+			child.logger.Name = child.name
+			if node.children == nil {
+				node.children = make(map[string]*registryNode)
+			}
+			node.children[part] = child
+			reg.update(child)
+		}
+		node = child
+	}
+	return node.logger
+}
+
+// SetLevel -- свой уровень узла и его потомков, у которых уровень не задан
+func (reg *Registry) SetLevel(name string, level int) {
+	reg.change(name, func(node *registryNode) {
+		if node == reg.root {
+			node.logger.swapSettings(func(live *liveSettings) { live.level = level })
+			return
+		}
+		node.level, node.hasLevel = level, true
+	})
+}
+
+// ResetLevel -- узел снова наследует уровень от родителя
+func (reg *Registry) ResetLevel(name string) {
+	reg.change(name, func(node *registryNode) { node.hasLevel = false })
+}
+
+// SetOut -- свой вывод узла и его потомков, nil -- в никуда
+func (reg *Registry) SetOut(name string, out io.Writer) {
+	reg.change(name, func(node *registryNode) {
+		if node == reg.root {
+			node.logger.Mu.Lock()
+			node.logger.Out = out
+			node.logger.Mu.Unlock()
+			return
+		}
+		node.out, node.hasOut = out, true
+	})
+}
+
+// ResetOut -- узел снова пишет в вывод родителя
+func (reg *Registry) ResetOut(name string) {
+	reg.change(name, func(node *registryNode) { node.out, node.hasOut = nil, false })
+}
+
+// SetFields -- свои поля узла, добавляются к полям родителя
+func (reg *Registry) SetFields(name string, fields ...Field) {
+	reg.change(name, func(node *registryNode) {
+		if node == reg.root {
+			node.logger.swapSettings(func(live *liveSettings) { live.fields = fields })
+			return
+		}
+		node.fields = fields
+	})
+}
+
+// Walk -- обход узлов от корня в глубину, дети -- по алфавиту. fn вернула false -- обход прекращается.
+// fn вызывается без блокировки иерархии, в ней можно менять настройки.
+func (reg *Registry) Walk(fn func(info LoggerInfo) bool) {
+	for _, info := range reg.List() {
+		if !fn(info) {
+			return
+		}
+	}
+}
+
+// List -- все узлы иерархии в порядке обхода Walk()
+func (reg *Registry) List() []LoggerInfo {
+	var list []LoggerInfo
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	reg.walk(reg.root, func(info LoggerInfo) bool {
+		list = append(list, info)
+		return true
+	})
+	return list
+}
+
+// Lookup -- сведения о существующем узле без его создания
+func (reg *Registry) Lookup(name string) (LoggerInfo, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	node := reg.find(normalizeName(name))
+	if node == nil {
+		return LoggerInfo{}, false
+	}
+	return node.info(reg.root), true
+}
+
+func (reg *Registry) walk(node *registryNode, fn func(info LoggerInfo) bool) bool {
+	if !fn(node.info(reg.root)) {
+		return false
+	}
+	names := make([]string, 0, len(node.children))
+	for part := range node.children {
+		names = append(names, part)
+	}
+	sort.Strings(names)
+	for _, part := range names {
+		if !reg.walk(node.children[part], fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// change -- изменение настроек узла (создается при отсутствии) и применение к нему и потомкам на ходу
+func (reg *Registry) change(name string, fn func(node *registryNode)) {
+	reg.Named(name)
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	node := reg.find(normalizeName(name))
+	fn(node)
+	if node == reg.root {
+		for _, child := range node.children {
+			reg.update(child)
+		}
+		return
+	}
+	reg.update(node)
+}
+
+// find -- узел по нормализованному имени или nil
+func (reg *Registry) find(name string) *registryNode {
+	node := reg.root
+	if name == "" {
+		return node
+	}
+	for _, part := range strings.Split(name, ".") {
+		if node = node.children[part]; node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// update -- перенастройка на ходу логгера узла и всех потомков: уровень, поля, флаги и формат (ссылкой на корень)
+// -- заменой настроек атомарно, вывод -- под мьютексом логгера. Прочие поля логгеров не пишутся,
+// запись через них идет без гонок.
+func (reg *Registry) update(node *registryNode) {
+	root, parent, lgr := reg.root.logger, node.parent.logger, node.logger
+	rootLive, parentLive := root.settings(), parent.settings()
+
+	live := &liveSettings{flags: rootLive.flags, stackLevel: rootLive.stackLevel, theme: rootLive.theme, level: parentLive.level,
+		format: rootLive.formatOf(root)}
+	if node.hasLevel {
+		live.level = node.level
+	}
+	live.fields = append(append(make([]Field, 0, len(parentLive.fields)+len(node.fields)), parentLive.fields...), node.fields...)
+	lgr.live.Store(live)
+
+	// вывод -- через владельца вывода, чтобы записи разных узлов не перемешивались
+	out, owner := node.out, (*BaseLogger)(nil)
+	if !node.hasOut {
+		owner = parent.owner()
+		owner.Mu.Lock()
+		out = owner.Out
+		owner.Mu.Unlock()
+	}
+	lgr.Mu.Lock()
+	lgr.Out = out
+	lgr.root.Store(owner)
+	lgr.Mu.Unlock()
+
+	for _, child := range node.children {
+		reg.update(child)
+	}
+}
+
+func (node *registryNode) info(root *registryNode) LoggerInfo {
+	return LoggerInfo{
+		Name:       node.name,
+		Level:      node.logger.GetLevel(),
+		IsLevelSet: node.hasLevel || node == root,
+		Logger:     node.logger,
+	}
+}
+
+// normalizeName -- имя без пустых частей: " db..pool. " -> "db.pool"
+func normalizeName(name string) string {
+	parts := strings.Split(strings.TrimSpace(name), ".")
+	names := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			names = append(names, part)
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// glRegistry -- иерархия логгеров процесса, корень создается при первом обращении из NewLogConfig()
+var glRegistry struct {
+	once sync.Once
+	reg  *Registry
+}
+
+// DefaultRegistry -- иерархия логгеров процесса @see Named()
+func DefaultRegistry() *Registry {
+	glRegistry.once.Do(func() {
+		var err error
+		root := (&BaseLogger{}).Init(NewLogConfig(), &err)
+		if err != nil {
+			Diagnose(err)
+		}
+		glRegistry.reg = NewRegistry(root)
+	})
+	return glRegistry.reg
+}
+
+// Named -- логгер с именем "db.pool" из иерархии процесса, наследует настройки "db" и корня @see Registry
+func Named(name string) *BaseLogger { return DefaultRegistry().Named(name) }
+
+// Walk -- обход логгеров иерархии процесса @see Registry.Walk()
+func Walk(fn func(info LoggerInfo) bool) { DefaultRegistry().Walk(fn) }
+
+// List -- логгеры иерархии процесса и их действующие уровни
+func List() []LoggerInfo { return DefaultRegistry().List() }
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Reload -- применение к работающему логгеру уровня, флагов, стека и выводов из новых настроек.
+// Новые выводы открываются заранее и подменяются под мьютексом вывода, записи не теряются. Уровень, флаги
+// и стек заменяются атомарно, идущие в других горутинах записи выводятся целиком по прежним или по новым.
+// Прежние открытые логгером файлы закрываются после подмены. При ошибке открытия выводы остаются прежними.
+// Порожденные через With() логгеры пишут в новые выводы, но уровень и флаги у них свои.
+func (baselog *BaseLogger) Reload(cfg *LogConfig) error {
+	if baselog.root.Load() != nil {
+		return fmt.Errorf("BaseLogger.Reload(): logger is derived by With(), reload its root")
+	}
+	// свои файлы закрываются, без cfg.Out основным остается только вывод, заданный логгеру снаружи
+	baselog.Mu.Lock()
+	current := baselog.Out
+	if len(baselog.files) > 0 {
+		current = nil
+	}
+	baselog.Mu.Unlock()
+	out, files, err := openSinks(cfg, current)
+	if err != nil {
+		closeAll(files)
+		return fmt.Errorf("BaseLogger.Reload() has: %s", err.Error())
+	}
+	flags, theme, err := colorSetup(cfg, out)
+	if err != nil {
+		closeAll(files)
+		return fmt.Errorf("BaseLogger.Reload() has: %s", err.Error())
+	}
+
+	baselog.Mu.Lock()
+	oldFiles := baselog.files
+	baselog.Out, baselog.files = out, files
+	baselog.Mu.Unlock()
+	baselog.reconfigure(func(live *liveSettings) {
+		live.level, live.flags, live.theme, live.stackLevel = cfg.Level, flags, theme, cfg.StackLevel
+	})
+
+	closeAll(oldFiles)
+	return nil
+}
+
+// WatchConfigFile -- перечитывание файла настроек при его изменении (проверка раз в interval) с применением
+// к логгеру @see LoadConfigFile(), Reload(). Переменные окружения -- с префиксом из настроек Init(). Ошибки передаются в onError, если задан. Возвращает функцию остановки.
+func (baselog *BaseLogger) WatchConfigFile(path string, interval time.Duration, onError func(err error)) (stop func()) {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	var modTime time.Time
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	}
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				if onError != nil {
+					onError(fmt.Errorf("WatchConfigFile() has: %s", err.Error()))
+				}
+				continue
+			}
+			if info.ModTime().Equal(modTime) && info.Size() == size {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+
+			cfg := (&LogConfig{EnvPrefix: baselog.envPrefix}).Init()
+			err = cfg.LoadFile(path)
+			if err == nil {
+				err = baselog.Reload(cfg)
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func closeAll(files []io.Closer) {
+	for _, file := range files {
+		_ = file.Close()
+	}
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// RotateFile -- файл лога с ротацией по размеру: при превышении MaxSize текущий файл переименовывается
+// в path.1, прежние path.1.. сдвигаются на номер дальше, хранится не более MaxBackups старых файлов.
+type RotateFile struct {
+	mu         sync.Mutex
+	Path       string
+	MaxSize    int
+	MaxBackups int
+
+	file *os.File
+	size int
+	// isFailed -- прошлая ротация не удалась, об ошибке уже сообщено
+	isFailed bool
+}
+
+// OpenRotateFile -- открыть (или создать) файл лога на дописывание с ротацией по размеру в байтах
+func OpenRotateFile(path string, maxSize, maxBackups int) (*RotateFile, error) {
+	rf := &RotateFile{Path: path, MaxSize: maxSize, MaxBackups: maxBackups}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write -- запись целиком в текущий файл, ротация -- перед записью, не влезающей в MaxSize.
+// Не удалась ротация -- запись идет в текущий файл, ошибка -- в диагностику один раз до удачной ротации @see Diagnose()
+func (rf *RotateFile) Write(data []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.MaxSize > 0 && rf.size > 0 && rf.size+len(data) > rf.MaxSize {
+		err := rf.rotate()
+		if err != nil && !rf.isFailed {
+			Diagnose(err)
+		}
+		rf.isFailed = err != nil
+	}
+	n, err := rf.file.Write(data)
+	rf.size += n
+	return n, err
+}
+
+// Close -- закрыть текущий файл
+func (rf *RotateFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+func (rf *RotateFile) open() error {
+	file, err := os.OpenFile(rf.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0664)
+	if err != nil {
+		return fmt.Errorf("RotateFile.open() has: %s", err.Error())
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("RotateFile.open() has: %s", err.Error())
+	}
+	rf.file = file
+	rf.size = int(info.Size())
+	return nil
+}
+
+// rotate -- сдвиг старых файлов path.N-1 -> path.N, текущего -- в path.1 и открытие нового.
+// Без MaxBackups текущий файл обрезается. При ошибке прежний файл остается открытым, ротация повторится
+// при следующей записи.
+func (rf *RotateFile) rotate() error {
+	if rf.MaxBackups <= 0 {
+		if err := rf.file.Truncate(0); err != nil {
+			return fmt.Errorf("RotateFile.rotate() has: %s", err.Error())
+		}
+		rf.size = 0
+		return nil
+	}
+
+	for num := rf.MaxBackups - 1; num > 0; num-- {
+		err := os.Rename(rf.backupName(num), rf.backupName(num+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("RotateFile.rotate() has: %s", err.Error())
+		}
+	}
+	// текущий файл мог уйти в path.1 при прошлой неудачной ротации
+	if err := os.Rename(rf.Path, rf.backupName(1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("RotateFile.rotate() has: %s", err.Error())
+	}
+	old := rf.file
+	if err := rf.open(); err != nil {
+		return err
+	}
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("RotateFile.rotate() has: %s", err.Error())
+	}
+	return nil
+}
+
+func (rf *RotateFile) backupName(num int) string {
+	return rf.Path + "." + strconv.Itoa(num)
+}
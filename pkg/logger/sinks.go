@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// openSinks -- открытие основного вывода cfg.Out и дополнительных cfg.Outputs, несколько -- через io.MultiWriter
+// current -- текущий вывод логгера, остается основным при пустом cfg.Out, без него -- stderr.
+// Возвращает и открытые файлы для закрытия.
+// Неоткрывшиеся выводы пропускаются, ошибка -- по первому из них.
+func openSinks(cfg *LogConfig, current io.Writer) (io.Writer, []io.Closer, error) {
+	var (
+		writers []io.Writer
+		files   []io.Closer
+		retErr  error
+	)
+	add := func(name string) {
+		out, file, err := openOut(name, cfg)
+		if err != nil && retErr == nil {
+			retErr = err
+		}
+		if out != nil {
+			writers = append(writers, out)
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	if cfg.Out != "" {
+		add(cfg.Out)
+	} else if current != nil {
+		writers = append(writers, current)
+	} else {
+		writers = append(writers, os.Stderr)
+	}
+	for _, name := range cfg.Outputs {
+		add(name)
+	}
+
+	switch len(writers) {
+	case 0:
+		return nil, files, retErr
+	case 1:
+		return writers[0], files, retErr
+	}
+	return io.MultiWriter(writers...), files, retErr
+}
+
+// openOut -- вывод по имени: "stdout"|"stderr"|"devnul" или путь к файлу, с ротацией при cfg.RotateSize > 0
+func openOut(name string, cfg *LogConfig) (io.Writer, io.Closer, error) {
+	switch name {
+	case "devnul":
+		return nil, nil, nil
+	case "stdout":
+		return os.Stdout, nil, nil
+	case "stderr":
+		return os.Stderr, nil, nil
+	}
+
+	if cfg.RotateSize > 0 {
+		file, err := OpenRotateFile(name, cfg.RotateSize, cfg.RotateBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		return file, file, nil
+	}
+
+	var file *os.File
+	var err error
+	if _, err = os.Stat(name); errors.Is(err, os.ErrNotExist) {
+		file, err = os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0664)
+	} else {
+		file, err = os.OpenFile(name, os.O_RDWR|os.O_APPEND, 0664)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("OpenFile has: %s", err.Error())
+	}
+	return file, file, nil
+}
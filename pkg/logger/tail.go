@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TailRecord -- копия записи лога для подписчиков @see Tail(). Поля -- логгера и записи, значения не копируются
+type TailRecord struct {
+	Time    time.Time
+	Level   int
+	Prefix  string
+	Logger  string
+	Message string
+	TraceId string
+	Fields  []Field
+	// Line -- запись как она выведена в Out
+	Line []byte
+}
+
+// TailFilter -- отбор записей подписчику, пустые условия не проверяются
+type TailFilter struct {
+	// Level -- записи этого уровня и важнее, LogNoneLevel -- все
+	Level int
+	// TraceId -- только с этим идентом трассировки
+	TraceId string
+	// Message -- только с текстом, подходящим под выражение
+	Message *regexp.Regexp
+}
+
+// Match -- подходит ли запись под условия
+func (filter *TailFilter) Match(rec *TailRecord) bool {
+	if filter.Level > LogNoneLevel && rec.Level > filter.Level {
+		return false
+	}
+	if filter.TraceId != "" && rec.TraceId != filter.TraceId {
+		return false
+	}
+	return filter.Message == nil || filter.Message.MatchString(rec.Message)
+}
+
+// TailSubscription -- подписка на записи всех логгеров процесса @see Tail()
+type TailSubscription struct {
+	// C -- записи подписчику. Закрывается отпиской Close() или при отключении медленного подписчика
+	C <-chan *TailRecord
+
+	ch      chan *TailRecord
+	filter  TailFilter
+	dropped atomic.Bool
+	once    sync.Once
+}
+
+// tail -- подписчики на записи. count -- их число для быстрой проверки в outlog() без мьютекса
+var tail struct {
+	mu          sync.Mutex
+	subscribers []*TailSubscription
+	count       atomic.Int32
+}
+
+// Tail -- подписка на записи всех логгеров процесса через канал с буфером size. Запись не ждет подписчика:
+// если его буфер полон, подписчик отключается (Dropped() == true) и его канал закрывается.
+// Приходят только записи, прошедшие уровень своего логгера.
+func Tail(size int, filter TailFilter) *TailSubscription {
+	sub := &TailSubscription{ch: make(chan *TailRecord, size), filter: filter}
+	sub.C = sub.ch
+	tail.mu.Lock()
+	tail.subscribers = append(tail.subscribers, sub)
+	tail.count.Store(int32(len(tail.subscribers)))
+	tail.mu.Unlock()
+	return sub
+}
+
+// Close -- отписка, канал C закрывается. Повторный вызов и вызов после отключения безопасны.
+func (sub *TailSubscription) Close() {
+	tail.mu.Lock()
+	sub.unsubscribe()
+	tail.mu.Unlock()
+}
+
+// Dropped -- подписчик был отключен за то, что не успевал забирать записи
+func (sub *TailSubscription) Dropped() bool {
+	return sub.dropped.Load()
+}
+
+// unsubscribe -- удаление из подписчиков и закрытие канала, под tail.mu
+func (sub *TailSubscription) unsubscribe() {
+	sub.once.Do(func() {
+		for i, other := range tail.subscribers {
+			if other == sub {
+				tail.subscribers = append(tail.subscribers[:i], tail.subscribers[i+1:]...)
+				break
+			}
+		}
+		tail.count.Store(int32(len(tail.subscribers)))
+		close(sub.ch)
+	})
+}
+
+// isTailed -- есть ли подписчики, без блокировок
+func isTailed() bool {
+	return tail.count.Load() > 0
+}
+
+// publishTail -- копия записи подходящим подписчикам. Никогда не ждет: медленные подписчики отключаются
+func (baselog *BaseLogger) publishTail(rec *LogRecord, line []byte) {
+	traceKey := baselog.GetTraceKey()
+	tailRec := &TailRecord{Time: rec.Now, Level: ToLevel(rec.Level), Prefix: rec.Level, Logger: baselog.Name, Message: rec.Message}
+	fields := baselog.GetFields()
+	tailRec.Fields = make([]Field, 0, len(fields)+len(rec.Fields))
+	tailRec.Fields = append(append(tailRec.Fields, fields...), rec.Fields...)
+	for _, field := range tailRec.Fields {
+		if traceId, ok := field.Value.(string); ok && field.Key == traceKey {
+			tailRec.TraceId = traceId
+		}
+	}
+	tailRec.Line = append([]byte(nil), line...)
+
+	tail.mu.Lock()
+	defer tail.mu.Unlock()
+	for i := 0; i < len(tail.subscribers); i++ {
+		sub := tail.subscribers[i]
+		if !sub.filter.Match(tailRec) {
+			continue
+		}
+		select {
+		case sub.ch <- tailRec:
+		default:
+			sub.dropped.Store(true)
+			sub.unsubscribe()
+			i--
+		}
+	}
+}
@@ -4,14 +4,75 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"sync"
 )
 
 type Errors []error
 
-var glErrors Errors
+// maxDiagnostics -- сколько последних диагностик хранится для GetErrorList()
+const maxDiagnostics = 100
 
-// GetErrorList -- получение списка ошибок за пределами пакета
-func GetErrorList() Errors { return glErrors }
+// diagnostics -- диагностика пакета (ошибки разбора настроек и т.п.): последние сообщения и подписчики
+var diagnostics struct {
+	mu          sync.Mutex
+	list        Errors
+	subscribers []chan error
+}
+
+// Diagnose -- добавить диагностику: в список последних и всем подписчикам. Не блокирует:
+// если буфер подписчика полон, сообщение для него теряется.
+func Diagnose(err error) {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+
+	if len(diagnostics.list) >= maxDiagnostics {
+		diagnostics.list = append(diagnostics.list[:0], diagnostics.list[1:]...)
+	}
+	diagnostics.list = append(diagnostics.list, err)
+	for _, ch := range diagnostics.subscribers {
+		select {
+		case ch <- err:
+		default:
+		}
+	}
+}
+
+// Diagnostics -- подписка на диагностику пакета через канал с буфером size. Возвращает канал и отписку, закрывающую его.
+func Diagnostics(size int) (<-chan error, func()) {
+	ch := make(chan error, size)
+	diagnostics.mu.Lock()
+	diagnostics.subscribers = append(diagnostics.subscribers, ch)
+	diagnostics.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			diagnostics.mu.Lock()
+			defer diagnostics.mu.Unlock()
+			for i, sub := range diagnostics.subscribers {
+				if sub == ch {
+					diagnostics.subscribers = append(diagnostics.subscribers[:i], diagnostics.subscribers[i+1:]...)
+					break
+				}
+			}
+			close(ch)
+		})
+	}
+}
+
+// GetErrorList -- получение копии списка последних ошибок (диагностик) за пределами пакета
+func GetErrorList() Errors {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+	return append(Errors(nil), diagnostics.list...)
+}
+
+// ClearErrorList -- очистить список последних ошибок
+func ClearErrorList() {
+	diagnostics.mu.Lock()
+	diagnostics.list = nil
+	diagnostics.mu.Unlock()
+}
 
 // LookupEnv @return -- значение переменной окружения или дефолтное (пофиг: нет или с ошибкой)
 func LookupEnv(name string, defVal interface{}) interface{} {
@@ -31,9 +92,7 @@ func ToInt(val interface{}) int {
 			return intVal
 		}
 	}
-	glErrors = append(glErrors, fmt.Errorf(
-		"ToInt() ERROR! value %v is not INTEGER or not be converted to INT!", val,
-	))
+	Diagnose(fmt.Errorf("ToInt() ERROR! value %v is not INTEGER or not be converted to INT!", val))
 
 	return 0
 }
@@ -0,0 +1,30 @@
+//go:build linux
+
+package logger
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// IsTerminal -- вывод является терминалом? Проверка через ioctl(TCGETS), как isatty()
+func IsTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok || file == nil {
+		return false
+	}
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	isTerm := false
+	err = conn.Control(func(fd uintptr) {
+		var termios syscall.Termios
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+		isTerm = errno == 0
+	})
+	return err == nil && isTerm
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package logger
+
+import "io"
+
+// IsTerminal -- определение терминала реализовано только для linux, иначе раскраска -- по LOG_COLOR=always
+func IsTerminal(out io.Writer) bool {
+	return false
+}
@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigError -- ошибка настройки: поле LogConfig, переменная окружения (если значение оттуда),
+// ошибочное значение, причина и подсказка
+type ConfigError struct {
+	Field      string
+	Env        string
+	Value      any
+	Reason     string
+	Suggestion string
+}
+
+func (e *ConfigError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("LogConfig.")
+	sb.WriteString(e.Field)
+	if e.Env != "" {
+		sb.WriteString(" (")
+		sb.WriteString(e.Env)
+		sb.WriteString(")")
+	}
+	sb.WriteString(fmt.Sprintf(" = %q: %s", fmt.Sprint(e.Value), e.Reason))
+	if e.Suggestion != "" {
+		sb.WriteString(", ")
+		sb.WriteString(e.Suggestion)
+	}
+	return sb.String()
+}
+
+// ConfigErrors -- все ошибки настроек сразу @see LogConfig.Validate(). Работает с errors.As() для *ConfigError
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs ConfigErrors) Unwrap() []error {
+	list := make([]error, len(errs))
+	for i, err := range errs {
+		list[i] = err
+	}
+	return list
+}
+
+// levelNames -- имена уровней для подсказок
+var levelNames = []string{"panic", "fatal", "error", "warn", "info", "debug"}
+
+// Validate -- проверка настроек. Возвращает nil или ConfigErrors со всеми найденными ошибками,
+// включая ошибочные значения переменных окружения, прочитанные Init()
+func (cfg *LogConfig) Validate() error {
+	errs := append(ConfigErrors(nil), cfg.problems...)
+	isReported := func(field string) bool {
+		for _, err := range cfg.problems {
+			if err.Field == field {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch cfg.Format {
+	case "", DefLoggerText, DefLoggerJson, DefLoggerLogfmt:
+	default:
+		errs = append(errs, &ConfigError{Field: "Format", Value: cfg.Format, Reason: "unknown format",
+			Suggestion: suggest(cfg.Format, []string{DefLoggerText, DefLoggerJson, DefLoggerLogfmt})})
+	}
+	if cfg.Level <= LogNoneLevel && !isReported("Level") {
+		errs = append(errs, &ConfigError{Field: "Level", Value: cfg.Level, Reason: "level disables all output",
+			Suggestion: `use "info" or Out "devnul" to silence the logger`})
+	}
+	if cfg.StackLevel < LogNoneLevel {
+		errs = append(errs, &ConfigError{Field: "StackLevel", Value: cfg.StackLevel, Reason: "negative level"})
+	}
+	switch cfg.Color {
+	case "", ColorAuto, ColorAlways, ColorNever:
+	default:
+		errs = append(errs, &ConfigError{Field: "Color", Value: cfg.Color, Reason: "unknown color mode",
+			Suggestion: suggest(cfg.Color, []string{ColorAuto, ColorAlways, ColorNever})})
+	}
+	if _, err := ParseTheme(cfg.Theme); err != nil {
+		errs = append(errs, &ConfigError{Field: "Theme", Value: cfg.Theme, Reason: err.Error(),
+			Suggestion: "expected default|256|rgb|mono or part=color list"})
+	}
+	if cfg.TimeZone != "" {
+		if _, err := time.LoadLocation(cfg.TimeZone); err != nil {
+			errs = append(errs, &ConfigError{Field: "TimeZone", Value: cfg.TimeZone, Reason: err.Error(),
+				Suggestion: `expected "UTC", "Local" or IANA name like "Europe/Moscow"`})
+		}
+	}
+	if cfg.Layout != "" {
+		if _, err := CompileLayout(cfg.Layout); err != nil {
+			errs = append(errs, &ConfigError{Field: "Layout", Value: cfg.Layout, Reason: err.Error()})
+		}
+	}
+	if unknown := cfg.Flags &^ LogAllFlags; unknown != 0 && !isReported("Flags") {
+		errs = append(errs, &ConfigError{Field: "Flags", Value: cfg.Flags, Reason: fmt.Sprintf("unknown flag bits %#x", unknown),
+			Suggestion: "expected names " + strings.Join(flagNames(), ",")})
+	}
+	for _, check := range []struct {
+		field string
+		val   int
+	}{{"MaxMessageLen", cfg.MaxMessageLen}, {"RotateSize", cfg.RotateSize}, {"RotateBackups", cfg.RotateBackups}} {
+		if check.val < 0 && !isReported(check.field) {
+			errs = append(errs, &ConfigError{Field: check.field, Value: check.val, Reason: "negative value"})
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// NewLogConfigStrict -- настройки из переменных окружения с проверкой, любая ошибка -- отказ
+func NewLogConfigStrict() (*LogConfig, error) {
+	cfg := NewLogConfig()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envName -- имя переменной, из которой взято значение: с префиксом, если она задана
+func (cfg *LogConfig) envName(name string) string {
+	if cfg.EnvPrefix != "" {
+		if _, ok := os.LookupEnv(cfg.EnvPrefix + name); ok {
+			return cfg.EnvPrefix + name
+		}
+	}
+	return name
+}
+
+// problem -- запоминание ошибочного значения переменной окружения для Validate() и в диагностику
+func (cfg *LogConfig) problem(field, env string, value any, reason, suggestion string) {
+	err := &ConfigError{Field: field, Env: cfg.envName(env), Value: value, Reason: reason, Suggestion: suggestion}
+	cfg.problems = append(cfg.problems, err)
+	Diagnose(err)
+}
+
+// envInt -- целое из переменной окружения, ошибочное -- 0 с запоминанием ошибки
+func (cfg *LogConfig) envInt(field, env string, defVal int) int {
+	val := cfg.LookupEnv(env, defVal)
+	if num, ok := val.(int); ok {
+		return num
+	}
+	num, err := strconv.Atoi(strings.TrimSpace(ToString(val)))
+	if err != nil {
+		cfg.problem(field, env, val, "not an integer", "")
+		return 0
+	}
+	if num < 0 {
+		cfg.problem(field, env, val, "negative value", "")
+	}
+	return num
+}
+
+// envLevel -- уровень из переменной окружения, isEmptyOk -- пустое значение допустимо (LogNoneLevel)
+func (cfg *LogConfig) envLevel(field, env, defVal string, isEmptyOk bool) int {
+	str := ToString(cfg.LookupEnv(env, defVal))
+	level := ToLevel(str)
+	if level != LogNoneLevel || (str == "" && isEmptyOk) {
+		return level
+	}
+	if _, err := strconv.Atoi(str); err == nil {
+		return level // числом можно задать и 0, проверяется в Validate()
+	}
+	cfg.problem(field, env, str, "unknown level", suggest(str, levelNames))
+	return level
+}
+
+// envFlags -- флаги из переменной окружения числом или списком имен @see parseFlags(), неизвестные имена пропускаются
+func (cfg *LogConfig) envFlags(field, env string, defVal int) int {
+	val := cfg.LookupEnv(env, defVal)
+	flags, unknown := parseFlags(val)
+	for _, name := range unknown {
+		cfg.problem(field, env, val, fmt.Sprintf("unknown flag '%s'", name), suggest(name, flagNames()))
+	}
+	return flags
+}
+
+// suggest -- подсказка: ближайшее известное значение или список допустимых
+func suggest(value string, known []string) string {
+	best, bestDist := "", len(value)/2+2
+	for _, name := range known {
+		if dist := editDistance(strings.ToLower(value), name); dist < bestDist {
+			best, bestDist = name, dist
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("did you mean %q?", best)
+	}
+	return "expected one of " + strings.Join(known, ",")
+}
+
+// editDistance -- расстояние Левенштейна для подсказок
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
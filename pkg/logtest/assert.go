@@ -0,0 +1,56 @@
+package logtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+// AssertLogged -- есть запись уровня level с сообщением по регулярному выражению. Отдает первую такую.
+func AssertLogged(tb testing.TB, entries Entries, level int, pattern string) Entry {
+	tb.Helper()
+	found := entries.FilterLevel(level).FilterMessage(pattern)
+	if len(found) == 0 {
+		tb.Fatalf("no record of level %d with message matching %q in:\n%s", level, pattern, entries)
+		return Entry{}
+	}
+	return found[0]
+}
+
+// AssertNotLogged -- нет записей уровня level с сообщением по регулярному выражению
+func AssertNotLogged(tb testing.TB, entries Entries, level int, pattern string) {
+	tb.Helper()
+	if found := entries.FilterLevel(level).FilterMessage(pattern); len(found) > 0 {
+		tb.Errorf("unexpected records of level %d matching %q:\n%s", level, pattern, found)
+	}
+}
+
+// AssertCount -- ровно count записей
+func AssertCount(tb testing.TB, entries Entries, count int) {
+	tb.Helper()
+	if len(entries) != count {
+		tb.Errorf("want %d records, got %d:\n%s", count, len(entries), entries)
+	}
+}
+
+// AssertNoErrors -- нет записей уровня ERROR и важнее
+func AssertNoErrors(tb testing.TB, entries Entries) {
+	tb.Helper()
+	if found := entries.FilterMinLevel(logger.LogErrorLevel); len(found) > 0 {
+		tb.Errorf("unexpected error records:\n%s", found)
+	}
+}
+
+// AssertField -- у записи есть поле key со значением value (сравнение по fmt.Sprint())
+func AssertField(tb testing.TB, entry Entry, key string, value any) {
+	tb.Helper()
+	val, ok := entry.Field(key)
+	if !ok {
+		tb.Errorf("record %q has no field %q", entry.String(), key)
+		return
+	}
+	if fmt.Sprint(val) != fmt.Sprint(value) {
+		tb.Errorf("record %q: field %q = %v, want %v", entry.String(), key, val, value)
+	}
+}
@@ -0,0 +1,300 @@
+// Package logtest -- логгер для тестов: запоминает записи структурами, без разбора вывода.
+// Реализует logger.Loggable и logger.Levelable, может дублировать записи в t.Log().
+//
+//	lgr := logtest.New(logtest.WithMirror(t))
+//	service := NewService(lgr)
+//	service.Do()
+//	logtest.AssertLogged(t, lgr.Entries(), logger.LogErrorLevel, "failed .*")
+package logtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+// Entry -- запомненная запись лога
+type Entry struct {
+	// Level -- уровень числом logger.LogPanicLevel..LogDebugLevel, Prefix -- как в логе: "INFO "
+	Level  int
+	Prefix string
+	Time   time.Time
+	// Caller -- место вызова "file.go:12", File -- полный путь
+	Caller string
+	File   string
+	Line   int
+	// Message -- текст после fmt.Sprintf()
+	Message string
+	// Fields -- поля логгера и записи
+	Fields []logger.Field
+	// TraceId -- значение поля с ключом трассировки, если есть
+	TraceId string
+}
+
+// Field -- значение поля записи по ключу, последнее из одноименных
+func (entry *Entry) Field(key string) (any, bool) {
+	for i := len(entry.Fields) - 1; i >= 0; i-- {
+		if entry.Fields[i].Key == key {
+			return entry.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// String -- запись строкой для t.Log() и сообщений об ошибках
+func (entry *Entry) String() string {
+	var sb strings.Builder
+	sb.WriteString(entry.Prefix)
+	sb.WriteString(" ")
+	sb.WriteString(entry.Caller)
+	sb.WriteString(" ")
+	sb.WriteString(entry.Message)
+	for _, field := range entry.Fields {
+		fmt.Fprintf(&sb, " %s=%v", field.Key, field.Value)
+	}
+	return sb.String()
+}
+
+// FatalExit -- значение паники вместо os.Exit() в Fatal(), тест может его перехватить
+type FatalExit struct {
+	Message string
+}
+
+// recorder -- общее хранилище записей логгера и порожденных через With()
+type recorder struct {
+	mu      sync.Mutex
+	entries Entries
+	mirror  testing.TB
+}
+
+// Logger -- тестовый логгер, запоминающий записи
+type Logger struct {
+	rec *recorder
+	// level -- атомарно: уровень меняют из теста во время записи из обработчиков
+	level    atomic.Int32
+	traceKey string
+	fields   []logger.Field
+}
+
+// Option -- настройка тестового логгера
+type Option func(lgr *Logger)
+
+// WithLevel -- наибольший записываемый уровень, по умолчанию -- logger.LogDebugLevel (все)
+func WithLevel(level int) Option {
+	return func(lgr *Logger) { lgr.level.Store(int32(level)) }
+}
+
+// WithMirror -- дублировать записи в tb.Log(), место вызова кода под тестом -- в тексте записи
+func WithMirror(tb testing.TB) Option {
+	return func(lgr *Logger) { lgr.rec.mirror = tb }
+}
+
+// WithTraceKey -- ключ поля трассировки для Entry.TraceId, по умолчанию -- logger.CtxTraceId
+func WithTraceKey(key string) Option {
+	return func(lgr *Logger) { lgr.traceKey = key }
+}
+
+// New -- тестовый логгер
+func New(opts ...Option) *Logger {
+	lgr := &Logger{rec: &recorder{}, traceKey: logger.CtxTraceId}
+	lgr.level.Store(logger.LogDebugLevel)
+	for _, opt := range opts {
+		opt(lgr)
+	}
+	return lgr
+}
+
+// NewT -- тестовый логгер с дублированием в t.Log()
+func NewT(tb testing.TB, opts ...Option) *Logger {
+	return New(append([]Option{WithMirror(tb)}, opts...)...)
+}
+
+func (lgr *Logger) GetLevel() int { return int(lgr.level.Load()) }
+
+// SetLevel -- изменить наибольший записываемый уровень, в т.ч. во время записи из других горутин
+func (lgr *Logger) SetLevel(level int) { lgr.level.Store(int32(level)) }
+
+// With -- логгер с добавленными полями, записи -- в то же хранилище
+func (lgr *Logger) With(fields ...logger.Field) *Logger {
+	child := &Logger{rec: lgr.rec, traceKey: lgr.traceKey}
+	child.level.Store(lgr.level.Load())
+	child.fields = append(append(make([]logger.Field, 0, len(lgr.fields)+len(fields)), lgr.fields...), fields...)
+	return child
+}
+
+// Entries -- копия всех записей по порядку
+func (lgr *Logger) Entries() Entries {
+	lgr.rec.mu.Lock()
+	defer lgr.rec.mu.Unlock()
+	return append(Entries(nil), lgr.rec.entries...)
+}
+
+// Len -- число записей
+func (lgr *Logger) Len() int {
+	lgr.rec.mu.Lock()
+	defer lgr.rec.mu.Unlock()
+	return len(lgr.rec.entries)
+}
+
+// Reset -- забыть все записи
+func (lgr *Logger) Reset() {
+	lgr.rec.mu.Lock()
+	lgr.rec.entries = nil
+	lgr.rec.mu.Unlock()
+}
+
+// Outlog -- запись с заданной глубины вызовов @see logger.Loggable
+func (lgr *Logger) Outlog(depth int, now time.Time, level, message string) {
+	lgr.OutlogFields(depth+1, now, level, message, nil)
+}
+
+// OutlogFields -- то же с полями записи (после полей логгера)
+func (lgr *Logger) OutlogFields(depth int, now time.Time, level, message string, fields []logger.Field) {
+	entry := Entry{
+		Level:   logger.ToLevel(level),
+		Prefix:  level,
+		Time:    now,
+		Message: message,
+	}
+	if _, file, line, ok := runtime.Caller(depth + 1); ok {
+		entry.File, entry.Line = file, line
+		entry.Caller = filepath.Base(file) + ":" + fmt.Sprint(line)
+	}
+	entry.Fields = append(append(make([]logger.Field, 0, len(lgr.fields)+len(fields)), lgr.fields...), fields...)
+	if val, ok := entry.Field(lgr.traceKey); ok {
+		entry.TraceId = fmt.Sprint(val)
+	}
+
+	lgr.rec.mu.Lock()
+	lgr.rec.entries = append(lgr.rec.entries, entry)
+	mirror := lgr.rec.mirror
+	lgr.rec.mu.Unlock()
+
+	if mirror != nil {
+		mirror.Log(entry.String())
+	}
+}
+
+func (lgr *Logger) Debug(msg string, args ...any) {
+	lgr.out(logger.LogDebugLevel, logger.LogDebugPrefix, msg, args, nil)
+}
+func (lgr *Logger) Info(msg string, args ...any) {
+	lgr.out(logger.LogInfoLevel, logger.LogInfoPrefix, msg, args, nil)
+}
+func (lgr *Logger) Warn(msg string, args ...any) {
+	lgr.out(logger.LogWarnLevel, logger.LogWarnPrefix, msg, args, nil)
+}
+func (lgr *Logger) Error(msg string, args ...any) {
+	lgr.out(logger.LogErrorLevel, logger.LogErrorPrefix, msg, args, nil)
+}
+
+// Fatal -- запись и паника FatalExit вместо завершения процесса. Уровень ниже LogFatalLevel -- ничего, как у BaseLogger
+func (lgr *Logger) Fatal(msg string, args ...any) {
+	if lgr.GetLevel() >= logger.LogFatalLevel {
+		message := fmt.Sprintf(msg, args...)
+		lgr.Outlog(1, time.Now(), logger.LogFatalPrefix, message)
+		panic(FatalExit{Message: message})
+	}
+}
+
+// Panic -- запись и паника текстом сообщения, как logger.BaseLogger.Panic(), и так же только при разрешенном уровне
+func (lgr *Logger) Panic(msg string, args ...any) {
+	if lgr.GetLevel() >= logger.LogPanicLevel {
+		message := fmt.Sprintf(msg, args...)
+		lgr.Outlog(1, time.Now(), logger.LogPanicPrefix, message)
+		panic(message)
+	}
+}
+
+func (lgr *Logger) WarnE(err error, msg string, args ...any) {
+	lgr.out(logger.LogWarnLevel, logger.LogWarnPrefix, msg, args, []logger.Field{logger.Err(err)})
+}
+func (lgr *Logger) ErrorE(err error, msg string, args ...any) {
+	lgr.out(logger.LogErrorLevel, logger.LogErrorPrefix, msg, args, []logger.Field{logger.Err(err)})
+}
+
+// out -- глубина 2: над out() метод уровня, над ним -- место вызова
+func (lgr *Logger) out(level int, prefix, msg string, args []any, fields []logger.Field) {
+	if lgr.GetLevel() >= level {
+		lgr.OutlogFields(2, time.Now(), prefix, fmt.Sprintf(msg, args...), fields)
+	}
+}
+
+// Entries -- список записей с выборками
+type Entries []Entry
+
+// FilterLevel -- записи заданного уровня
+func (entries Entries) FilterLevel(level int) Entries {
+	return entries.Filter(func(entry *Entry) bool { return entry.Level == level })
+}
+
+// FilterMinLevel -- записи заданного уровня и важнее (ERROR -- ошибки, FATAL и PANIC)
+func (entries Entries) FilterMinLevel(level int) Entries {
+	return entries.Filter(func(entry *Entry) bool { return entry.Level <= level })
+}
+
+// FilterMessage -- записи с сообщением по регулярному выражению, ошибочное выражение -- паника
+func (entries Entries) FilterMessage(pattern string) Entries {
+	re := regexp.MustCompile(pattern)
+	return entries.Filter(func(entry *Entry) bool { return re.MatchString(entry.Message) })
+}
+
+// FilterField -- записи с полем key, равным value (сравнение по fmt.Sprint() для ошибок и т.п.)
+func (entries Entries) FilterField(key string, value any) Entries {
+	want := fmt.Sprint(value)
+	return entries.Filter(func(entry *Entry) bool {
+		val, ok := entry.Field(key)
+		return ok && fmt.Sprint(val) == want
+	})
+}
+
+// FilterTrace -- записи с заданным идентификатором трассировки
+func (entries Entries) FilterTrace(traceId string) Entries {
+	return entries.Filter(func(entry *Entry) bool { return entry.TraceId == traceId })
+}
+
+// Filter -- записи, для которых fn вернула true
+func (entries Entries) Filter(fn func(entry *Entry) bool) Entries {
+	var found Entries
+	for i := range entries {
+		if fn(&entries[i]) {
+			found = append(found, entries[i])
+		}
+	}
+	return found
+}
+
+// Messages -- тексты записей по порядку
+func (entries Entries) Messages() []string {
+	msgs := make([]string, len(entries))
+	for i := range entries {
+		msgs[i] = entries[i].Message
+	}
+	return msgs
+}
+
+// AllFields -- поля всех записей подряд, по порядку
+func (entries Entries) AllFields() []logger.Field {
+	var fields []logger.Field
+	for i := range entries {
+		fields = append(fields, entries[i].Fields...)
+	}
+	return fields
+}
+
+// String -- записи построчно, для сообщений об ошибках
+func (entries Entries) String() string {
+	lines := make([]string, len(entries))
+	for i := range entries {
+		lines[i] = entries[i].String()
+	}
+	return strings.Join(lines, "\n")
+}
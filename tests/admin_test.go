@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	loggrpc "github.com/Arhat109/logger/pkg/grpc"
+	"github.com/Arhat109/logger/pkg/grpc/adminpb"
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestAdminService(t *testing.T) {
+	var err error
+	root := &logger.BaseLogger{}
+	root.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogWarnLevel}, &err)
+	root.Out = &bytes.Buffer{}
+	reg := logger.NewRegistry(root)
+	pool := reg.Named("db.pool")
+	reg.Named("api")
+
+	conn := dialBufconn(t, func(server *grpc.Server) { loggrpc.RegisterAdmin(server, reg) }, nil)
+	client := adminpb.NewLogAdminClient(conn)
+	ctx := context.Background()
+
+	level, err := client.GetLevel(ctx, &adminpb.GetLevelRequest{Logger: "db.pool"})
+	if err != nil || level.Level != "warn" || level.IsSet {
+		t.Fatalf("get level: %v %v", level, err)
+	}
+	if _, err = client.GetLevel(ctx, &adminpb.GetLevelRequest{Logger: "no.such"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown logger: %v", err)
+	}
+	if _, err = client.SetLevel(ctx, &adminpb.SetLevelRequest{Logger: "db", Level: "verbose"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown level: %v", err)
+	}
+
+	// часть иерархии "db" -- в DEBUG на время, потомок без своего уровня следует за ней
+	resp, err := client.SetLevel(ctx, &adminpb.SetLevelRequest{Logger: "db", Level: "debug", Ttl: durationpb.New(300 * time.Millisecond)})
+	if err != nil || resp.Current.Level != "debug" || resp.Previous.Level != "warn" || resp.Previous.IsSet || resp.Current.RestoreAt == nil {
+		t.Fatalf("set level: %v %v", resp, err)
+	}
+	if pool.GetLevel() != logger.LogDebugLevel {
+		t.Errorf("descendant level: %d", pool.GetLevel())
+	}
+	list, err := client.ListLoggers(ctx, &adminpb.ListLoggersRequest{Prefix: "db"})
+	if err != nil || len(list.Loggers) != 2 || list.Loggers[0].Logger != "db" || list.Loggers[1].Logger != "db.pool" ||
+		list.Loggers[1].Level != "debug" || list.Loggers[1].IsSet {
+		t.Errorf("list loggers: %v %v", list, err)
+	}
+	if list, _ = client.ListLoggers(ctx, &adminpb.ListLoggersRequest{}); len(list.Loggers) != 4 {
+		t.Errorf("all loggers: %v", list)
+	}
+
+	// по истечении ttl -- снова наследуемый уровень. Уровень меняет таймер, поэтому читается через сервис
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if level, err = client.GetLevel(ctx, &adminpb.GetLevelRequest{Logger: "db.pool"}); err != nil || level.Level == "warn" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil || level.Level != "warn" {
+		t.Errorf("db.pool level after ttl: %v %v", level, err)
+	}
+	level, err = client.GetLevel(ctx, &adminpb.GetLevelRequest{Logger: "db"})
+	if err != nil || level.Level != "warn" || level.IsSet || level.RestoreAt != nil {
+		t.Errorf("level after ttl: %v %v", level, err)
+	}
+
+	// без ttl -- навсегда, корень -- пустым именем
+	if resp, err = client.SetLevel(ctx, &adminpb.SetLevelRequest{Level: "error"}); err != nil || resp.Current.Logger != "" || root.GetLevel() != logger.LogErrorLevel {
+		t.Errorf("set root level: %v %v", resp, err)
+	}
+}
+
+// TestAdminSetLevelWhileLogging -- смена уровня через сервис и возврат по ttl во время записи, для go test -race
+func TestAdminSetLevelWhileLogging(t *testing.T) {
+	var err error
+	root := &logger.BaseLogger{}
+	root.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogWarnLevel}, &err)
+	root.Out = io.Discard
+	reg := logger.NewRegistry(root)
+	pool := reg.Named("db.pool")
+
+	conn := dialBufconn(t, func(server *grpc.Server) { loggrpc.RegisterAdmin(server, reg) }, nil)
+	client := adminpb.NewLogAdminClient(conn)
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					pool.Debug("debug")
+					pool.Warn("warn")
+					time.Sleep(10 * time.Microsecond)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		level := "debug"
+		if i%2 == 1 {
+			level = "error"
+		}
+		if _, err = client.SetLevel(ctx, &adminpb.SetLevelRequest{Logger: "db", Level: level, Ttl: durationpb.New(time.Millisecond)}); err != nil {
+			t.Fatalf("set level: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(done)
+	wg.Wait()
+
+	// все уровни на время -- возвращаются к наследуемому
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.GetLevel() != logger.LogWarnLevel && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if pool.GetLevel() != logger.LogWarnLevel {
+		t.Errorf("level after ttl: %d", pool.GetLevel())
+	}
+}
+
+func TestTailLogs(t *testing.T) {
+	var err error
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = &bytes.Buffer{}
+
+	// медленный подписчик отключается, вывод его не ждет
+	slow := logger.Tail(2, logger.TailFilter{Message: regexp.MustCompile("^slow ")})
+	for i := 0; i < 3; i++ {
+		lgr.Warn("slow %d", i)
+	}
+	if !slow.Dropped() || len(slow.C) != 2 {
+		t.Errorf("slow subscriber: dropped=%v queued=%d", slow.Dropped(), len(slow.C))
+	}
+	for range slow.C {
+	}
+	slow.Close()
+
+	conn := dialBufconn(t, func(server *grpc.Server) { loggrpc.RegisterAdmin(server, nil) }, nil)
+	client := adminpb.NewLogAdminClient(conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.TailLogs(ctx, &adminpb.TailLogsRequest{MessageRegex: "(bad"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("bad regex: %v", err)
+	}
+
+	stream, err = client.TailLogs(ctx, &adminpb.TailLogsRequest{MinLevel: "warn", TraceId: "tail-1", MessageRegex: "^tail "})
+	if err != nil {
+		t.Fatalf("tail logs: %v", err)
+	}
+	traced := lgr.Ctx(lgr.ContextWithTrace(ctx, "tail-1"))
+	// подписка появляется на сервере не сразу: пишем, пока не придет первая запись
+	received := make(chan *adminpb.LogRecord, 16)
+	go func() {
+		defer close(received)
+		for {
+			rec, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case received <- rec:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	synced := false
+	for i := 0; i < 500 && !synced; i++ {
+		traced.Warn("tail sync")
+		select {
+		case <-received:
+			synced = true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if !synced {
+		t.Fatal("tail subscription is not ready")
+	}
+
+	traced.Info("tail info")
+	lgr.Ctx(lgr.ContextWithTrace(ctx, "tail-2")).Error("tail other trace")
+	traced.Error("skip me")
+	traced.Error("tail error %d", 42)
+
+	var rec *adminpb.LogRecord
+	for rec = range received {
+		if rec.Message != "tail sync" {
+			break
+		}
+	}
+	if rec == nil || rec.Message != "tail error 42" || rec.Level != "error" || rec.TraceId != "tail-1" ||
+		rec.Fields[logger.CtxTraceId] != "tail-1" || rec.Time == nil || !bytes.Contains([]byte(rec.Line), []byte("tail error 42")) {
+		t.Errorf("tail record: %v", rec)
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func TestColorTheme(t *testing.T) {
+	theme, err := logger.ParseTheme("error=196,time=1:2:3,key=33")
+	if err != nil {
+		t.Fatalf("ParseTheme(): %v", err)
+	}
+	if got, want := theme.LevelColor(logger.LogErrorPrefix), logger.Color256(196); got != want {
+		t.Errorf("error color = %q, want %q", got, want)
+	}
+	if got, want := theme.Time, "\x1b[38;2;1;2;3m"; got != want {
+		t.Errorf("time color = %q, want %q", got, want)
+	}
+	if theme.LevelColor(logger.LogInfoPrefix) != logger.DefaultTheme.LevelColor(logger.LogInfoPrefix) {
+		t.Errorf("info color is not inherited from default theme")
+	}
+	for _, spec := range []string{"error", "error=256", "bogus=1", "time=1:2"} {
+		if _, err := logger.ParseTheme(spec); err == nil {
+			t.Errorf("ParseTheme(%q): error expected", spec)
+		}
+	}
+
+	// в буфер -- не терминал: auto и never без раскраски, always -- с темой
+	for mode, isColored := range map[string]bool{logger.ColorAuto: false, logger.ColorNever: false, logger.ColorAlways: true} {
+		out := &bytes.Buffer{}
+		lgr := logger.BaseLogger{}
+		lgr.Init(&logger.LogConfig{
+			Out: "devnul", Color: mode, Theme: "key=33",
+			Flags: logger.LogLevelColored | logger.LogTime, Level: logger.LogInfoLevel,
+		}, &err)
+		lgr.Out = out
+		lgr.With(logger.Field{Key: "user", Value: 1}).Warn("hi")
+
+		got := out.String()
+		if isColored != strings.Contains(got, "\x1b[") {
+			t.Errorf("mode %s: colored output %q", mode, got)
+		}
+		if isColored && !strings.Contains(got, " "+logger.Color256(33)+"user\x1b[0m=1") {
+			t.Errorf("mode %s: key is not colored: %q", mode, got)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"log.json": `{"format":"logfmt","level":"debug","flags":["date","shortfile"],"max_message":100,"rotate":{"max_size":2048}}`,
+		"log.yaml": "format: logfmt\nlevel: debug\nflags: [date, shortfile]\nmax_message: 100\nrotate:\n  max_size: 2048\n",
+		"log.toml": "format = \"logfmt\"\nlevel = \"debug\"\nflags = [\"date\", \"shortfile\"]\nmax_message = 100\n[rotate]\nmax_size = 2048\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := logger.LoadConfigFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cfg.Format != "logfmt" || cfg.Level != logger.LogDebugLevel || cfg.Flags != logger.LogDate|logger.LogShortFile ||
+			cfg.MaxMessageLen != 100 || cfg.RotateSize != 2048 {
+			t.Errorf("%s: wrong config %+v", name, cfg)
+		}
+	}
+
+	// переменные окружения важнее файла
+	t.Setenv(logger.EnvLoggerLevel, "error")
+	cfg, err := logger.LoadConfigFile(filepath.Join(dir, "log.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != logger.LogErrorLevel {
+		t.Errorf("env level is not preferred: %d", cfg.Level)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	_ = os.WriteFile(bad, []byte(`{"flags":["date","bogus"]}`), 0644)
+	if _, err = logger.LoadConfigFile(bad); err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("unknown flag error expected, got %v", err)
+	}
+}
+
+func TestRotateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := logger.OpenRotateFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err = file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = file.Close()
+
+	for name, want := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		if got, _ := os.ReadFile(name); string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err = os.Stat(path + ".3"); err == nil {
+		t.Errorf("too many backups")
+	}
+}
+
+func TestRotateFileFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := logger.OpenRotateFile(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// path.1 -- каталог: ротация не может переименовать текущий файл
+	if err = os.MkdirAll(filepath.Join(path+".1", "busy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	diag, cancel := logger.Diagnostics(10)
+	defer cancel()
+	// запись идет в текущий файл, ошибка ротации -- в диагностику один раз
+	for _, line := range []string{"first\n", "second\n", "more\n"} {
+		if _, err = file.Write([]byte(line)); err != nil {
+			t.Fatalf("write with failed rotation: %v", err)
+		}
+	}
+	if len(diag) != 1 {
+		t.Fatalf("want 1 rotation diagnostic, got %d", len(diag))
+	}
+	if got := <-diag; !strings.Contains(got.Error(), "RotateFile.rotate()") {
+		t.Errorf("diagnostic: %v", got)
+	}
+
+	// после устранения причины ротация и запись идут дальше, прежний файл не потерян
+	if err = os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = file.Write([]byte("third\n")); err != nil {
+		t.Fatalf("write after failed rotation: %v", err)
+	}
+	for name, want := range map[string]string{path: "third\n", path + ".1": "first\nsecond\nmore\n"} {
+		if got, _ := os.ReadFile(name); string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	out := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte("level: info\nout: "+out+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := logger.LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr := logger.BaseLogger{}
+	lgr.Init(cfg, &err)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 10)
+	stop := lgr.WatchConfigFile(path, 5*time.Millisecond, func(err error) { errs <- err })
+	defer stop()
+
+	lgr.Debug("hidden")
+	// запись из другой горутины во время перечитывания -- для go test -race
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				lgr.Info("busy")
+				// подмена вывода под мьютексом, как Registry.SetOut() у корня
+				lgr.Mu.Lock()
+				out := lgr.Out
+				lgr.Out = out
+				lgr.Mu.Unlock()
+			}
+		}
+	}()
+	defer wg.Wait()
+	defer close(done)
+	if err = os.WriteFile(path, []byte("level: debug\nout: "+out+"\nflags: [shortfile]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chtimes(path, time.Now().Add(time.Second), time.Now().Add(time.Second))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for lgr.GetLevel() != logger.LogDebugLevel && time.Now().Before(deadline) {
+		select {
+		case err = <-errs:
+			t.Fatal(err)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	if lgr.GetLevel() != logger.LogDebugLevel {
+		t.Fatalf("level is not reloaded")
+	}
+	lgr.Debug("shown")
+
+	got, _ := os.ReadFile(out)
+	if strings.Contains(string(got), "hidden") || !strings.Contains(string(got), "shown") {
+		t.Errorf("output after reload:\n%s", got)
+	}
+}
+
+func TestEnvConfig(t *testing.T) {
+	t.Setenv(logger.EnvLoggerFlags, "date, time,micro,shortfile,color,trace")
+	t.Setenv(logger.EnvLoggerLevel, "45")
+	t.Setenv(logger.EnvLoggerOut, "stdout")
+	t.Setenv("DB_"+logger.EnvLoggerLevel, "debug")
+	t.Setenv("DB_"+logger.EnvLoggerFlags, "12")
+
+	cfg := logger.NewLogConfig()
+	wantFlags := logger.LogDate | logger.LogTime | logger.LogMicroSeconds | logger.LogShortFile | logger.LogLevelColored | logger.LogWithTrace
+	if cfg.Flags != wantFlags || cfg.Level != 45 || cfg.Out != "stdout" {
+		t.Errorf("wrong env config: flags=%d level=%d out=%s", cfg.Flags, cfg.Level, cfg.Out)
+	}
+
+	dbCfg := logger.NewLogConfigPrefix("DB_")
+	if dbCfg.Flags != 12 || dbCfg.Level != logger.LogDebugLevel || dbCfg.Out != "stdout" {
+		t.Errorf("wrong prefixed config: flags=%d level=%d out=%s", dbCfg.Flags, dbCfg.Level, dbCfg.Out)
+	}
+
+	// тот же разбор флагов у ToFlags() и у настроек, умолчание -- дата, время, файл и цвет
+	if logger.ToFlags("Date, time,micro,shortfile,color,trace") != wantFlags || logger.ToFlags("12") != 12 {
+		t.Errorf("ToFlags() differs from env parsing")
+	}
+	_ = os.Unsetenv(logger.EnvLoggerFlags) // вернет t.Setenv() выше
+	wantFlags = logger.LogDate | logger.LogTime | logger.LogShortFile | logger.LogLevelColored
+	if cfg = logger.NewLogConfig(); cfg.Flags != wantFlags || cfg.Flags != logger.ToFlags("date,time,shortfile,color") {
+		t.Errorf("default flags: %d, want %d", cfg.Flags, wantFlags)
+	}
+	if logger.ToFlags("all") != logger.LogAllFlags {
+		t.Errorf("all flags: %d", logger.ToFlags("all"))
+	}
+
+	if logger.ToLevel("bogus") != logger.LogNoneLevel || logger.ToLevel(logger.LogWarnPrefix) != logger.LogWarnLevel {
+		t.Errorf("ToLevel() of names")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	diag, cancel := logger.Diagnostics(10)
+	defer cancel()
+
+	t.Setenv(logger.EnvLoggerLevel, "inof")
+	t.Setenv(logger.EnvLoggerFlags, "date,tiem")
+	t.Setenv(logger.EnvLoggerTimeZone, "Mars/Olympus")
+
+	if _, err := logger.NewLogConfigStrict(); err == nil {
+		t.Fatalf("strict config accepted wrong env")
+	}
+	err := logger.NewLogConfig().Validate()
+	var errs logger.ConfigErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("want 3 config errors, got %v", err)
+	}
+	var cfgErr *logger.ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Errorf("errors.As(*ConfigError) failed")
+	}
+	for _, cfgErr = range errs {
+		if cfgErr.Field == "Level" && (cfgErr.Env != logger.EnvLoggerLevel || cfgErr.Value != "inof") {
+			t.Errorf("level error: %#v", cfgErr)
+		}
+	}
+	for _, want := range []string{`did you mean "info"?`, `did you mean "time"?`, "Mars/Olympus"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("no %q in %s", want, err)
+		}
+	}
+
+	select {
+	case got := <-diag:
+		if !strings.Contains(got.Error(), "tiem") {
+			t.Errorf("diagnostic: %v", got)
+		}
+	default:
+		t.Errorf("no diagnostic sent")
+	}
+
+	if err = (&logger.LogConfig{Level: logger.LogInfoLevel, Format: "logfmt"}).Validate(); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+}
+
+// TestValidateOrder -- ошибки всегда в одном порядке, неизвестные флаги -- тоже ошибка настроек
+func TestValidateOrder(t *testing.T) {
+	cfg := &logger.LogConfig{Level: logger.LogInfoLevel, Flags: logger.LogDate | 1<<20, MaxMessageLen: -1, RotateSize: -1, RotateBackups: -1}
+	first := cfg.Validate()
+	var errs logger.ConfigErrors
+	if !errors.As(first, &errs) || len(errs) != 4 {
+		t.Fatalf("want 4 config errors, got %v", first)
+	}
+	for i, field := range []string{"Flags", "MaxMessageLen", "RotateSize", "RotateBackups"} {
+		if errs[i].Field != field {
+			t.Errorf("error %d: field %s, want %s", i, errs[i].Field, field)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		if err := cfg.Validate(); err.Error() != first.Error() {
+			t.Fatalf("unstable errors:\n%s\n%s", first, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	_ = os.WriteFile(path, []byte("flags: [date, shortfiel]\n"), 0644)
+	_, err := logger.LoadConfigFile(path)
+	var cfgErr *logger.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "Flags" || !strings.Contains(err.Error(), `did you mean "shortfile"?`) {
+		t.Errorf("config file flag error: %v", err)
+	}
+}
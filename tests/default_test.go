@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func TestDefault(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := (&logger.BaseLogger{}).Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Flags: logger.LogShortFile, Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	old := logger.SetDefault(lgr)
+	defer logger.SetDefault(old)
+	if logger.Default() != lgr || logger.Named("") != lgr {
+		t.Fatalf("default logger is not swapped")
+	}
+
+	line := thisLine() + 1
+	logger.Infof("hello %d", 1)
+	logger.Debug("hidden")
+	logger.ErrorE(errors.New("boom"), "failed")
+
+	want := "level=info caller=default_test.go:" + strconv.Itoa(line) + " msg=\"hello 1\"\n" +
+		"level=error caller=default_test.go:" + strconv.Itoa(line+2) + " msg=failed error=boom error.type=*errors.errorString\n"
+	if got := out.String(); got != want {
+		t.Errorf("output:\n got %q\nwant %q", got, want)
+	}
+}
+
+// TestTextCaller -- в тексте и шаблоне место вызова -- код пользователя, а не логгер
+func TestTextCaller(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := (&logger.BaseLogger{}).Init(&logger.LogConfig{Out: "devnul", Flags: logger.LogShortFile, Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	line := thisLine() + 1
+	lgr.Info("text")
+	lgr.Layout, _ = logger.CompileLayout("%{caller} %{func}")
+	lgr.Info("layout")
+
+	want := "\nINFO :default_test.go#" + strconv.Itoa(line+10000)[1:] + " text\n" +
+		"default_test.go#" + strconv.Itoa(line+10002)[1:] + " github.com/Arhat109/logger/tests.TestTextCaller#" + strconv.Itoa(line+10002)[1:] + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("output:\n got %q\nwant %q", got, want)
+	}
+}
+
+// TestSetDefaultWhileLogging -- замена логгера процесса во время записи через него и через Named()
+func TestSetDefaultWhileLogging(t *testing.T) {
+	var err error
+	first := (&logger.BaseLogger{}).Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Flags: logger.LogPrefix, Level: logger.LogInfoLevel}, &err)
+	first.Out = io.Discard
+	out := &bytes.Buffer{}
+	second := (&logger.BaseLogger{}).Init(&logger.LogConfig{Out: "devnul", Format: "json", Flags: logger.LogPrefix, Level: logger.LogWarnLevel}, &err)
+	second.Out = io.Discard
+
+	old := logger.SetDefault(first)
+	defer logger.SetDefault(old)
+	worker := logger.Named("svc.worker")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 300; n++ {
+				worker.Warn("named")
+				logger.Info("default")
+				logger.Named("svc").With(logger.Field{Key: "n", Value: n}).Warn("with")
+			}
+		}()
+	}
+	for n := 0; n < 100; n++ {
+		logger.SetDefault(second)
+		logger.SetDefault(first)
+	}
+	wg.Wait()
+
+	logger.SetDefault(second)
+	second.Mu.Lock()
+	second.Out = out
+	second.Mu.Unlock()
+	worker.Info("hidden")
+	worker.Warn("json")
+	if got := out.String(); !strings.HasSuffix(got, `"message":"json","logger":"svc.worker"}`+"\n") || strings.Contains(got, "hidden") {
+		t.Errorf("named logger after SetDefault(): %q", got)
+	}
+}
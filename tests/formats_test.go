@@ -0,0 +1,313 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+// thisLine -- номер строки вызова, для проверки места логирования
+func thisLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestLogfmt(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Format: "logfmt", Flags: logger.LogShortFile, Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	line := thisLine() + 1
+	lgr.Info("say \"hi\"\nnext=%d", 1)
+
+	want := "level=info caller=formats_test.go:" + strconv.Itoa(line) + " msg=\"say \\\"hi\\\"\\nnext=1\"\n"
+	if got := out.String(); got != want {
+		t.Errorf("logfmt:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestLogfmtValue(t *testing.T) {
+	for val, want := range map[string]string{
+		"plain":   "plain",
+		"":        `""`,
+		"a b":     `"a b"`,
+		"k=v":     `"k=v"`,
+		"\x1b[0m": `"\u001b[0m"`,
+	} {
+		var buf []byte
+		logger.FormatLogfmtValue(&buf, val)
+		if string(buf) != want {
+			t.Errorf("FormatLogfmtValue(%q) = %s, want %s", val, buf, want)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{
+		Layout:  "[%-6{level}] %{caller} %{trace}|%{msg}|%{fields} %5.3{func}%%",
+		Flags:   logger.LogShortFile,
+		Level:   logger.LogInfoLevel,
+		TraceId: "trace_id",
+	}, &err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.Out = out
+
+	line := thisLine() + 1
+	lgr.With(logger.Field{Key: "trace_id", Value: "abc"}, logger.Field{Key: "n", Value: 5}).Info("hi")
+
+	want := fmt.Sprintf("[INFO  ] formats_test.go#%04d abc|hi|n=5   git%%\n", line)
+	if got := out.String(); got != want {
+		t.Errorf("layout:\n got %q\nwant %q", got, want)
+	}
+
+	// идент трассировки этой записи важнее идента логгера и в полях не повторяется
+	out.Reset()
+	line = thisLine() + 1
+	lgr.With(logger.Field{Key: "trace_id", Value: "abc"}).OutlogFields(0, time.Now(), logger.LogInfoPrefix, "call",
+		[]logger.Field{{Key: "trace_id", Value: "def"}, {Key: "n", Value: 6}})
+	want = fmt.Sprintf("[INFO  ] formats_test.go#%04d def|call|n=6   git%%\n", line)
+	if got := out.String(); got != want {
+		t.Errorf("layout with record trace id:\n got %q\nwant %q", got, want)
+	}
+
+	if _, err = logger.CompileLayout("%{unknown}"); err == nil {
+		t.Error("CompileLayout() must fail on unknown element")
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	now := time.Date(2026, 10, 18, 13, 4, 5, 123456789, time.FixedZone("MSK", 3*3600))
+	for format, want := range map[string]string{
+		logger.TimeFormatUnix:                           "1792317845",
+		logger.TimeFormatUnixMilli:                      "1792317845123",
+		logger.TimeFormatISOWeek:                        "2026-W42-7",
+		logger.TimeLayout(logger.TimeFormatRFC3339Nano): "2026-10-18T13:04:05.123456789+03:00",
+		"15:04": "13:04",
+	} {
+		var buf []byte
+		logger.FormatTimestamp(&buf, now, format)
+		if string(buf) != want {
+			t.Errorf("FormatTimestamp(%q) = %s, want %s", format, buf, want)
+		}
+	}
+
+	var buf []byte
+	logger.FormatTime(&buf, now, logger.LogUTC|logger.LogTime|logger.LogMicroSeconds)
+	if want := " 10:04:05.123456"; string(buf) != want {
+		t.Errorf("FormatTime(LogUTC) = %q, want %q", buf, want)
+	}
+}
+
+func TestJson(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{
+		IsJson:     true,
+		Level:      logger.LogInfoLevel,
+		TimeFormat: logger.TimeFormatUnix,
+		TimeZone:   "UTC",
+	}, &err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.Out = out
+
+	line := thisLine() + 1
+	lgr.With(logger.Field{Key: "n", Value: 5}).Info("say \"hi\"")
+
+	var mess struct {
+		logger.JsonMessage
+		DateTime int64 `json:"date_time"`
+		N        int   `json:"n"`
+	}
+	if err = json.Unmarshal(out.Bytes(), &mess); err != nil {
+		t.Fatalf("%s: %q", err, out.String())
+	}
+	if mess.Message != "say \"hi\"" || mess.N != 5 || mess.LineNum != line || mess.DateTime == 0 {
+		t.Errorf("json: %q", out.String())
+	}
+}
+
+func TestStack(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Level: logger.LogInfoLevel, StackLevel: logger.LogErrorLevel}, &err)
+	lgr.Out = out
+
+	lgr.Warn("no stack")
+	if strings.Contains(out.String(), "\t") {
+		t.Errorf("stack on WARN: %q", out.String())
+	}
+
+	lgr.Error("with stack")
+	text := out.String()
+	if !strings.Contains(text, "\ttests.TestStack\n\t\t") && !strings.Contains(text, "/tests.TestStack\n\t\t") {
+		t.Errorf("no caller frame in stack: %q", text)
+	}
+	if strings.Contains(text, "BaseLogger") {
+		t.Errorf("logger frames in stack: %q", text)
+	}
+
+	out.Reset()
+	lgr.Format = logger.LogFormatJson
+	lgr.Error("with stack")
+	var mess struct {
+		Stacktrace []struct {
+			Func string `json:"func"`
+			File string `json:"file"`
+			Line int    `json:"line"`
+		} `json:"stacktrace"`
+	}
+	if err = json.Unmarshal(out.Bytes(), &mess); err != nil {
+		t.Fatalf("%s: %q", err, out.String())
+	}
+	if len(mess.Stacktrace) == 0 || !strings.HasSuffix(mess.Stacktrace[0].Func, "tests.TestStack") {
+		t.Errorf("json stacktrace: %q", out.String())
+	}
+}
+
+// TestGetCallers -- прежний GetCallers(skip) и его вариант с буфером вызывающего
+func TestGetCallers(t *testing.T) {
+	frames := logger.GetCallers(1)
+	if frames == nil {
+		t.Fatal("GetCallers(1): nil")
+	}
+	if frame, _ := frames.Next(); !strings.HasSuffix(frame.Function, "tests.TestGetCallers") {
+		t.Errorf("GetCallers(1): %q", frame.Function)
+	}
+
+	var buf [8]uintptr
+	pcs := logger.GetCallerPCs(1, buf[:])
+	if len(pcs) < 2 || len(pcs) > len(buf) {
+		t.Fatalf("GetCallerPCs(1): %d frames", len(pcs))
+	}
+	if frame, _ := runtime.CallersFrames(pcs).Next(); !strings.HasSuffix(frame.Function, "tests.TestGetCallers") {
+		t.Errorf("GetCallerPCs(1): %q", frame.Function)
+	}
+}
+
+// TestFormatFileLine -- глубина как у runtime.Caller() и GetCaller(), как была до логгера с записями
+func TestFormatFileLine(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	logger.FormatFileLine(&buf, 1, true)
+	line := thisLine() - 1
+	if want := fmt.Sprintf("formats_test.go#%04d", line); string(buf) != want {
+		t.Errorf("FormatFileLine: got %q, want %q", buf, want)
+	}
+
+	buf = buf[:0]
+	logger.FormatFuncLine(&buf, 2)
+	line = thisLine() - 1
+	if want := fmt.Sprintf("tests.TestFormatFileLine#%04d", line); !strings.HasSuffix(string(buf), want) {
+		t.Errorf("FormatFuncLine: got %q, want suffix %q", buf, want)
+	}
+}
+
+type codeError struct{ code int }
+
+func (e *codeError) Error() string               { return "code " + strconv.Itoa(e.code) }
+func (e *codeError) ErrorFields() []logger.Field { return []logger.Field{{Key: "code", Value: e.code}} }
+
+type joinError []error
+
+func (e joinError) Error() string   { return "joined" }
+func (e joinError) Unwrap() []error { return e }
+
+func TestErrorE(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	cause := fmt.Errorf("wrap: %w", joinError{&codeError{5}, fmt.Errorf("plain")})
+	lgr.ErrorE(cause, "failed %d", 1)
+
+	want := `level=error msg="failed 1" error="wrap: joined" error.type=*fmt.wrapError` +
+		` error.chain="tests.joinError > [*tests.codeError | *errors.errorString]" error.code=5` + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("logfmt:\n got %s\nwant %s", got, want)
+	}
+
+	out.Reset()
+	lgr.Format = logger.LogFormatJson
+	lgr.ErrorE(cause, "failed")
+	var mess struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Causes  []struct {
+				Type   string `json:"type"`
+				Causes []struct {
+					Type string `json:"type"`
+					Code int    `json:"code"`
+				} `json:"causes"`
+			} `json:"causes"`
+		} `json:"error"`
+	}
+	if err = json.Unmarshal(out.Bytes(), &mess); err != nil {
+		t.Fatalf("%s: %q", err, out.String())
+	}
+	if mess.Error.Message != "wrap: joined" || len(mess.Error.Causes) != 1 ||
+		len(mess.Error.Causes[0].Causes) != 2 || mess.Error.Causes[0].Causes[0].Code != 5 {
+		t.Errorf("json error: %s", out.String())
+	}
+}
+
+func TestMaxMessageLen(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Format: "logfmt", Level: logger.LogInfoLevel, MaxMessageLen: 5}, &err)
+	lgr.Out = out
+
+	lgr.Info("абвгд") // 10 байт, режется по границе символа
+	if want := "level=info msg=\"аб...[truncated 6 bytes]\"\n"; out.String() != want {
+		t.Errorf("truncated:\n got %q\nwant %q", out.String(), want)
+	}
+
+	out.Reset()
+	lgr.MaxMessageLen = 0
+	long := strings.Repeat("x", 100*1024)
+	lgr.Info(long)
+	if !strings.Contains(out.String(), long) {
+		t.Error("long message is lost")
+	}
+}
+
+func TestEscapedText(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	lgr.Info("first\n\x1b[31mINFO : fake\r\xff\xc2\x9b\n")
+	want := "\nINFO : first\n    \\x1b[31mINFO : fake\\r\\xff\\u009b\n"
+	if got := out.String(); got != want {
+		t.Errorf("escaped:\n got %q\nwant %q", got, want)
+	}
+
+	out.Reset()
+	lgr.RawText = true
+	lgr.Info("raw\x1b[0m")
+	if want = "\nINFO : raw\x1b[0m\n"; out.String() != want {
+		t.Errorf("raw:\n got %q\nwant %q", out.String(), want)
+	}
+}
@@ -0,0 +1,474 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	loggrpc "github.com/Arhat109/logger/pkg/grpc"
+	"github.com/Arhat109/logger/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/grpclog"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer -- запоминает идент трассировки, который видит обработчик
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	lgr     *logger.BaseLogger
+	traceId chan string
+	err     error
+	panic   any
+}
+
+func (srv *healthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	srv.traceId <- srv.lgr.TraceId(ctx)
+	if srv.panic != nil {
+		panic(srv.panic)
+	}
+	if srv.err != nil {
+		return nil, srv.err
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (srv *healthServer) Watch(_ *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	srv.traceId <- srv.lgr.TraceId(stream.Context())
+	if srv.panic != nil {
+		panic(srv.panic)
+	}
+	return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+// startGrpc -- сервер health на bufconn с заданными перехватчиками и клиент к нему
+func startGrpc(t *testing.T, srv healthpb.HealthServer, opts ...grpc.ServerOption) *grpc.ClientConn {
+	return startGrpcClient(t, srv, opts)
+}
+
+// startGrpcClient -- то же со своими настройками клиента
+func startGrpcClient(t *testing.T, srv healthpb.HealthServer, opts []grpc.ServerOption, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	return dialBufconn(t, func(server *grpc.Server) { healthpb.RegisterHealthServer(server, srv) }, opts, dialOpts...)
+}
+
+// dialBufconn -- сервер на bufconn со своими сервисами и клиент к нему, остановка -- по завершении теста
+func dialBufconn(t *testing.T, register func(server *grpc.Server), opts []grpc.ServerOption, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 16)
+	server := grpc.NewServer(opts...)
+	register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial("bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestGrpcTracing(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	srv := &healthServer{lgr: lgr, traceId: make(chan string, 1)}
+	conn := startGrpc(t, srv,
+		grpc.ChainUnaryInterceptor(loggrpc.UnaryTracingInterceptor(lgr), loggrpc.UnaryLoggerInterceptor(lgr)),
+		grpc.ChainStreamInterceptor(loggrpc.StreamTracingInterceptor(lgr), loggrpc.StreamLoggerInterceptor(lgr)))
+	client := healthpb.NewHealthClient(conn)
+
+	// идент из метаданных клиента доходит до обработчика и возвращается в заголовке
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), loggrpc.MetadataTraceId, "abc-1")
+	if _, err = client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if got := <-srv.traceId; got != "abc-1" || strings.Join(header.Get(loggrpc.MetadataTraceId), "") != "abc-1" {
+		t.Errorf("unary trace id: handler %q, header %v", got, header)
+	}
+	if !strings.Contains(out.String(), `msg="grpc call" method=/grpc.health.v1.Health/Check code=OK`) || !strings.Contains(out.String(), "trace_id=abc-1") {
+		t.Errorf("unary record: %q", out.String())
+	}
+
+	// traceparent принимается, без метаданных -- новый идент
+	ctx = metadata.AppendToOutgoingContext(context.Background(), loggrpc.MetadataTraceparent,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+	header, _ = stream.Header()
+	if got := <-srv.traceId; got != "4bf92f3577b34da6a3ce929d0e0e4736" || header.Get(loggrpc.MetadataTraceId)[0] != got {
+		t.Errorf("stream trace id: handler %q, header %v", got, header)
+	}
+
+	if _, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if got := <-srv.traceId; len(got) != 36 {
+		t.Errorf("generated trace id: %q", got)
+	}
+}
+
+func TestGrpcClient(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	srv := &healthServer{lgr: lgr, traceId: make(chan string, 1)}
+	conn := startGrpcClient(t, srv,
+		[]grpc.ServerOption{
+			grpc.UnaryInterceptor(loggrpc.UnaryTracingInterceptor(lgr)),
+			grpc.StreamInterceptor(loggrpc.StreamTracingInterceptor(lgr)),
+		},
+		grpc.WithUnaryInterceptor(loggrpc.UnaryClientInterceptor(lgr)),
+		grpc.WithStreamInterceptor(loggrpc.StreamClientInterceptor(lgr)))
+	client := healthpb.NewHealthClient(conn)
+
+	// идент из контекста клиента -- у обработчика сервера и в записи клиента
+	ctx := lgr.ContextWithTrace(context.Background(), "cli-7")
+	ctx = metadata.AppendToOutgoingContext(ctx, loggrpc.MetadataRetryAttempt, "2")
+	if _, err = client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if got := <-srv.traceId; got != "cli-7" {
+		t.Errorf("server got trace id %q", got)
+	}
+	unary := regexp.MustCompile(`^level=info msg="grpc client call" method=/grpc.health.v1.Health/Check target=bufnet ` +
+		`code=OK duration=\S+ attempt=2 trace_id=cli-7\n$`)
+	if !unary.MatchString(out.String()) {
+		t.Errorf("unary record: %q", out.String())
+	}
+
+	// уровень по коду, без идента в контексте -- новый, тот же у сервера и в записи
+	out.Reset()
+	srv.err = status.Error(codes.NotFound, "no such service")
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("check: %v", err)
+	}
+	if got := <-srv.traceId; len(got) != 36 || !strings.HasPrefix(out.String(), "level=warn") ||
+		!strings.Contains(out.String(), "code=NotFound") || !strings.Contains(out.String(), "trace_id="+got) {
+		t.Errorf("trace id %q, record: %q", got, out.String())
+	}
+
+	// запись о потоке -- по его завершении
+	out.Reset()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	<-srv.traceId
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if err != io.EOF || !strings.Contains(out.String(), "method=/grpc.health.v1.Health/Watch") || !strings.Contains(out.String(), "code=OK") {
+		t.Errorf("stream: %v, record: %q", err, out.String())
+	}
+}
+
+// collectDesc -- сервис с потоком только от клиента: ответ один, после всех запросов
+var collectDesc = grpc.ServiceDesc{
+	ServiceName: "test.Collect",
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{{StreamName: "Collect", ClientStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			for {
+				if err := stream.RecvMsg(&healthpb.HealthCheckRequest{}); err == io.EOF {
+					return stream.SendMsg(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+				} else if err != nil {
+					return err
+				}
+			}
+		}}},
+}
+
+func TestGrpcClientStreams(t *testing.T) {
+	var err error
+	out := &lockedBuffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	srv := &healthServer{lgr: lgr, traceId: make(chan string, 1)}
+	conn := dialBufconn(t, func(server *grpc.Server) {
+		healthpb.RegisterHealthServer(server, srv)
+		server.RegisterService(&collectDesc, struct{}{})
+	}, nil, grpc.WithStreamInterceptor(loggrpc.StreamClientInterceptor(lgr)))
+
+	// поток от клиента: запись по единственному ответу, без ожидания io.EOF
+	collect, err := conn.NewStream(context.Background(), &collectDesc.Streams[0], "/test.Collect/Collect")
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err = collect.SendMsg(&healthpb.HealthCheckRequest{Service: "svc"}); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	_ = collect.CloseSend()
+	if err = collect.RecvMsg(&healthpb.HealthCheckResponse{}); err != nil {
+		t.Fatalf("close and recv: %v", err)
+	}
+	record := regexp.MustCompile(`^level=info msg="grpc client stream" method=/test.Collect/Collect target=bufnet code=OK duration=\S+ trace_id=\S+\n$`)
+	if !record.MatchString(out.String()) {
+		t.Errorf("client stream record: %q", out.String())
+	}
+
+	// брошенный поток сервера: запись по отмене контекста, одна
+	out.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err = healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	<-srv.traceId
+	cancel()
+	for deadline := time.Now().Add(5 * time.Second); out.String() == "" && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := out.String(); strings.Count(got, "\n") != 1 || !strings.HasPrefix(got, "level=warn") ||
+		!strings.Contains(got, "method=/grpc.health.v1.Health/Watch") || !strings.Contains(got, "code=Canceled") {
+		t.Errorf("cancelled stream record: %q", got)
+	}
+}
+
+func TestGrpcPolicy(t *testing.T) {
+	for code, want := range map[codes.Code]int{codes.OK: logger.LogInfoLevel, codes.NotFound: logger.LogWarnLevel,
+		codes.Unavailable: logger.LogWarnLevel, codes.Internal: logger.LogErrorLevel} {
+		if got := loggrpc.CodeLevel(code); got != want {
+			t.Errorf("CodeLevel(%s) = %d, want %d", code, got, want)
+		}
+	}
+
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+	other := &bytes.Buffer{}
+	otherLgr := &logger.BaseLogger{}
+	otherLgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogDebugLevel}, &err)
+	otherLgr.Out = other
+
+	srv := &healthServer{lgr: lgr, traceId: make(chan string, 1)}
+	conn := startGrpc(t, srv,
+		grpc.ChainUnaryInterceptor(
+			loggrpc.UnaryLoggerInterceptor(lgr,
+				loggrpc.WithCodeLevel(codes.NotFound, logger.LogInfoLevel),
+				loggrpc.WithPeer(), loggrpc.WithDeadline(), loggrpc.WithMetadata("X-User"),
+				loggrpc.WithPayloads(0), loggrpc.WithRedact("service")),
+			loggrpc.UnaryLoggerInterceptor(otherLgr, loggrpc.WithMethods("other.Service/*"))),
+		grpc.StreamInterceptor(loggrpc.StreamLoggerInterceptor(lgr, loggrpc.WithoutMethods("/grpc.health.v1.Health/Watch"))))
+	client := healthpb.NewHealthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-user", "bob", "x-secret", "hidden")
+	if _, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "payments"}); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	<-srv.traceId
+	record := regexp.MustCompile(`^level=info msg="grpc call" method=/grpc.health.v1.Health/Check code=OK duration=\S+ ` +
+		`peer=bufconn deadline=\S+s md.x-user=bob request="{\\"service\\": ?\\"\[REDACTED\]\\"}" ` +
+		`response="{\\"status\\": ?\\"SERVING\\"}"\n$`)
+	if !record.MatchString(out.String()) {
+		t.Errorf("record: %q", out.String())
+	}
+
+	// свой уровень для кода, без ответа при ошибке; исключенный метод не пишется
+	out.Reset()
+	srv.err = status.Error(codes.NotFound, "unknown service")
+	_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	<-srv.traceId
+	srv.err = nil
+	stream, _ := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	<-srv.traceId
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if got := out.String(); !strings.HasPrefix(got, "level=info") || !strings.Contains(got, "code=NotFound") ||
+		strings.Contains(got, "response=") || strings.Count(got, "\n") != 1 {
+		t.Errorf("records: %q", got)
+	}
+	if other.Len() != 0 {
+		t.Errorf("method filter: %q", other.String())
+	}
+}
+
+// grpcLogOut -- вывод адаптера внутренних сообщений gRPC. Адаптер ставится до запуска серверов в тестах:
+// grpclog.SetLoggerV2() не защищен от одновременной работы транспорта gRPC
+var grpcLogOut = &lockedBuffer{}
+var grpcLgr = (&logger.BaseLogger{}).Init(&logger.LogConfig{
+	Out: "devnul", Format: "logfmt", Flags: logger.LogShortFile, Level: logger.LogWarnLevel,
+}, new(error))
+
+func init() {
+	grpcLgr.Out = grpcLogOut
+	gl := loggrpc.NewGrpcLogger(grpcLgr)
+	gl.Verbosity = 2
+	grpclog.SetLoggerV2(gl)
+}
+
+// lockedBuffer -- буфер под мьютексом: в него пишут и горутины транспорта gRPC
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(data []byte) (int, error) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.buf.Write(data)
+}
+
+func (lb *lockedBuffer) Reset() {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.buf.Reset()
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.buf.String()
+}
+
+func TestGrpcLogger(t *testing.T) {
+	grpcLogOut.Reset()
+	line := thisLine() + 1
+	grpclog.Infof("hidden %d", 1)
+	grpclog.Warningln("direct", 2)
+	grpclog.Component("test").Errorf("component %d", 3)
+
+	// свои записи транспорта gRPC от прошлых тестов тут не нужны
+	var got string
+	for _, record := range strings.SplitAfter(grpcLogOut.String(), "\n") {
+		if strings.Contains(record, "caller=grpc_test.go:") {
+			got += record
+		}
+	}
+	want := "level=warn caller=grpc_test.go:" + strconv.Itoa(line+1) + " msg=\"direct 2\"\n" +
+		"level=error caller=grpc_test.go:" + strconv.Itoa(line+2) + " msg=\"[test] component 3\"\n"
+	if got != want {
+		t.Errorf("output:\n got %q\nwant %q", got, want)
+	}
+	if !grpclog.V(2) || grpclog.V(3) {
+		t.Errorf("verbosity threshold 2: V(2)=%v V(3)=%v", grpclog.V(2), grpclog.V(3))
+	}
+}
+
+func TestGrpcStreamAccounting(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogDebugLevel}, &err)
+	lgr.Out = out
+
+	srv := &healthServer{lgr: lgr, traceId: make(chan string, 1)}
+	conn := startGrpc(t, srv, grpc.ChainStreamInterceptor(
+		loggrpc.StreamTracingInterceptor(lgr), loggrpc.StreamLoggerInterceptor(lgr, loggrpc.WithMessages())))
+	client := healthpb.NewHealthClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), loggrpc.MetadataTraceId, "st-1")
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "svc"})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	<-srv.traceId
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []*regexp.Regexp{
+		regexp.MustCompile(`^level=debug msg="grpc stream message" method=/grpc.health.v1.Health/Watch direction=received seq=1 size=5 trace_id=st-1$`),
+		regexp.MustCompile(`^level=debug msg="grpc stream message" method=/grpc.health.v1.Health/Watch direction=sent seq=1 size=2 trace_id=st-1$`),
+		regexp.MustCompile(`^level=info msg="grpc stream" method=/grpc.health.v1.Health/Watch code=OK duration=\S+ ` +
+			`msgs_sent=1 msgs_received=1 bytes_sent=2 bytes_received=5 first_sent=\S+ first_received=\S+ trace_id=st-1$`),
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("records:\n%s", out.String())
+	}
+	for i, re := range want {
+		if !re.MatchString(lines[i]) {
+			t.Errorf("record %d: %q", i, lines[i])
+		}
+	}
+}
+
+func TestGrpcRecovery(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	srv := &healthServer{lgr: lgr, traceId: make(chan string, 1), panic: "boom"}
+	conn := startGrpc(t, srv,
+		grpc.ChainUnaryInterceptor(loggrpc.UnaryTracingInterceptor(lgr), loggrpc.UnaryRecoveryInterceptor(lgr)),
+		grpc.ChainStreamInterceptor(loggrpc.StreamTracingInterceptor(lgr), loggrpc.StreamRecoveryInterceptor(lgr,
+			loggrpc.WithRecoveryLevel(logger.LogErrorLevel),
+			loggrpc.WithRecoveryHandler(func(_ context.Context, method string, value any) error {
+				return status.Errorf(codes.Unavailable, "%s: try later", method)
+			}))))
+	client := healthpb.NewHealthClient(conn)
+
+	// паника -- codes.Internal без подробностей, в логе -- значение, метод, трассировка и стек с места паники
+	ctx := metadata.AppendToOutgoingContext(context.Background(), loggrpc.MetadataTraceId, "pn-1")
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	<-srv.traceId
+	if st := status.Convert(err); st.Code() != codes.Internal || st.Message() != "internal error" {
+		t.Errorf("unary status: %v", err)
+	}
+	text := out.String()
+	if !strings.HasPrefix(text, `level=panic msg="grpc handler panic" method=/grpc.health.v1.Health/Check panic=boom `+
+		`panic_type=string trace_id=pn-1 stacktrace="`) || !strings.Contains(text, "tests.(*healthServer).Check") {
+		t.Errorf("unary record: %s", text)
+	}
+
+	// свой уровень и свой ответ; сервер продолжает работать
+	out.Reset()
+	srv.panic = errors.New("stream broken")
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	<-srv.traceId
+	if st := status.Convert(err); st.Code() != codes.Unavailable || st.Message() != "/grpc.health.v1.Health/Watch: try later" {
+		t.Errorf("stream status: %v", err)
+	}
+	if text = out.String(); !strings.HasPrefix(text, `level=error msg="grpc handler panic" method=/grpc.health.v1.Health/Watch error="stream broken"`) {
+		t.Errorf("stream record: %s", text)
+	}
+
+	srv.panic = nil
+	if _, err = client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("server after panic: %v", err)
+	}
+	<-srv.traceId
+}
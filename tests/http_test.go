@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	loghttp "github.com/Arhat109/logger/pkg/http"
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func TestHttpMiddleware(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	var gotTrace string
+	handler := loghttp.Middleware(lgr, loghttp.WithAccessFormat(loghttp.AccessStructured|loghttp.AccessCombined))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotTrace = lgr.TraceId(r.Context())
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("no such page"))
+		}))
+
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/pages/1?x=\"y\"", nil)
+	req.Header.Set("traceparent", "00-"+traceId+"-00f067aa0ba902b7-01")
+	req.Header.Set("User-Agent", "tester")
+	req.RemoteAddr = "10.0.0.1:5555"
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if gotTrace != traceId || resp.Header().Get(loghttp.HeaderRequestId) != traceId {
+		t.Errorf("trace id: ctx %q, header %q", gotTrace, resp.Header().Get(loghttp.HeaderRequestId))
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected structured and combined records, got:\n%s", out.String())
+	}
+	structured := regexp.MustCompile(`^level=warn msg="http request" method=GET path=/pages/1 status=404 bytes=12 ` +
+		`duration=\S+ peer=10\.0\.0\.1:5555 user_agent=tester trace_id=` + traceId + `$`)
+	if !structured.MatchString(lines[0]) {
+		t.Errorf("structured record: %q", lines[0])
+	}
+	combined := regexp.MustCompile(`^10\.0\.0\.1 - - \[\d\d/\w{3}/\d{4}:\d\d:\d\d:\d\d [+-]\d{4}\] ` +
+		`"GET /pages/1\?x=\\"y\\" HTTP/1\.1" 404 12 "-" "tester"$`)
+	if !combined.MatchString(lines[1]) {
+		t.Errorf("combined record: %q", lines[1])
+	}
+
+	// X-Request-Id принимается, если traceparent нет или он испорчен; без обоих -- генерируется свой
+	for _, tc := range []struct{ traceparent, requestId, want string }{
+		{"00-" + traceId + "-0000000000000000-01", "req-42", "req-42"},
+		{"", "", ""},
+	} {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.traceparent != "" {
+			req.Header.Set("traceparent", tc.traceparent)
+		}
+		if tc.requestId != "" {
+			req.Header.Set(loghttp.HeaderRequestId, tc.requestId)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if tc.want != "" && gotTrace != tc.want || tc.want == "" && len(gotTrace) != 36 {
+			t.Errorf("traceparent %q, X-Request-Id %q: got trace id %q", tc.traceparent, tc.requestId, gotTrace)
+		}
+	}
+}
+
+// TestHttpMiddlewareHijack -- обработчику за промежуточными доступны Hijacker (websocket) и Pusher
+func TestHttpMiddlewareHijack(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := &logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	var pushErr error
+	done := make(chan struct{})
+	handler := loghttp.Middleware(lgr)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pusher, ok := w.(http.Pusher); ok {
+			pushErr = pusher.Push("/style.css", nil)
+		}
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\nhello\n")
+		_ = brw.Flush()
+	}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, _ = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: test\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n"))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("upgrade response: %v %v", resp, err)
+	}
+	<-done
+
+	// HTTP/1.1 без push: метод есть, но возвращает http.ErrNotSupported
+	if !errors.Is(pushErr, http.ErrNotSupported) {
+		t.Errorf("push: %v", pushErr)
+	}
+	if !strings.Contains(out.String(), "method=GET path=/ws status=101") {
+		t.Errorf("access record: %q", out.String())
+	}
+}
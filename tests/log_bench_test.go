@@ -5,7 +5,10 @@ import (
 	"github.com/Arhat109/logger/pkg/logger"
 	"log"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 var glbuf = [1024]byte{}
@@ -28,68 +31,96 @@ func Benchmark_Baselog(b *testing.B) {
 	}
 }
 
-var stdLgr = log.New(bufWriter, "", log.Ldate)
+var logfmtLgr = logger.BaseLogger{}
 
-func Benchmark_Stdlog(b *testing.B) {
+func Benchmark_Logfmt(b *testing.B) {
+	var err error
+	logfmtLgr.Init(&logger.LogConfig{
+		Format: "logfmt",
+		Flags:  logger.LogDate | logger.LogTime | logger.LogMicroSeconds,
+		Level:  logger.LogDebugLevel,
+	}, &err)
+	logfmtLgr.Out = bufWriter
 	runtime.GC()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		bufWriter.Reset()
-		stdLgr.Printf("any info message")
+		logfmtLgr.Debug("this is a \"quoted\" message")
 	}
 }
 
-/*
-type BufSyncer struct {
-	buf *bytes.Buffer
-}
+var layoutLgr = logger.BaseLogger{}
 
-func (bs *BufSyncer) Write(content []byte) (int, error) {
-	bs.buf.Reset()
-	bs.buf.Write(content)
-	return len(content), nil
+func Benchmark_Layout(b *testing.B) {
+	var err error
+	layoutLgr.Init(&logger.LogConfig{
+		Layout: "%{time:RFC3339} [%-5{level}] %-20.20{caller} %{msg}",
+		Flags:  logger.LogShortFile,
+		Level:  logger.LogDebugLevel,
+	}, &err)
+	layoutLgr.Out = bufWriter
+	runtime.GC()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bufWriter.Reset()
+		layoutLgr.Debug("this is a message")
+	}
 }
-func (bs *BufSyncer) Sync() error { return nil }
-
-var bufSyncer = BufSyncer{buf: bufWriter}
 
-var zapLgr = zap.New(
-	zapcore.NewCore(
-		zapcore.NewConsoleEncoder(
-			zapcore.EncoderConfig{
-				TimeKey:  "T",
-				LevelKey: "L",
-				//				CallerKey:        "C",
-				MessageKey:  "M",
-				LineEnding:  zapcore.DefaultLineEnding,
-				EncodeLevel: zapcore.LowercaseLevelEncoder,
-				EncodeTime:  zapcore.RFC3339TimeEncoder,
-				//				EncodeCaller:     zapcore.ShortCallerEncoder,
-				ConsoleSeparator: ": ",
-			},
-		), &bufSyncer, zap.DebugLevel,
-	))
+var jsonLgr = logger.BaseLogger{}
 
-func Benchmark_Zaplog(b *testing.B) {
+func Benchmark_Json(b *testing.B) {
+	var err error
+	jsonLgr.Init(&logger.LogConfig{
+		IsJson:     true,
+		TimeFormat: logger.TimeFormatRFC3339Nano,
+		TimeZone:   "UTC",
+		Level:      logger.LogDebugLevel,
+	}, &err)
+	jsonLgr.Out = bufWriter
+	runtime.GC()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		zapLgr.Info("any info message")
+		bufWriter.Reset()
+		jsonLgr.Debug("this is a message")
 	}
 }
 
-var GlRes string
+var sizeLgr = logger.BaseLogger{}
 
-func Benchmark_Sptintf(b *testing.B) {
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		GlRes = fmt.Sprintf("any info message")
+// Benchmark_MessageSize -- сообщения разной длины: в пределах буфера, на его границе и много больше
+func Benchmark_MessageSize(b *testing.B) {
+	var err error
+	sizeLgr.Init(&logger.LogConfig{
+		Flags: logger.LogDate | logger.LogTime,
+		Level: logger.LogDebugLevel,
+	}, &err)
+	sizeLgr.Out = bufWriter
+
+	for _, size := range []int{100, 1024, 64 * 1024} {
+		message := strings.Repeat("x", size)
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			runtime.GC()
+			b.ReportAllocs()
+			b.SetBytes(int64(size))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				bufWriter.Reset()
+				sizeLgr.Outlog(0, time.Now(), logger.LogDebugPrefix, message)
+			}
+		})
 	}
 }
 
-func Benchmark_Caller(b *testing.B) {
+var stdLgr = log.New(bufWriter, "", log.Ldate)
+
+func Benchmark_Stdlog(b *testing.B) {
+	runtime.GC()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, GlRes, _, _ = runtime.Caller(1)
+		bufWriter.Reset()
+		stdLgr.Printf("any info message")
 	}
 }
-*/
+
+// Сравнение с zap, logrus и zerolog -- в отдельном модуле ./benchmarks, чтобы их зависимости не попадали в основной
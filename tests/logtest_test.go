@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Arhat109/logger/pkg/logger"
+	"github.com/Arhat109/logger/pkg/logtest"
+)
+
+// doWorkLine -- строка записи об ошибке в doWork()
+var doWorkLine int
+
+// типовое применение: код под тестом знает только logger.Levelable
+func doWork(lgr logger.Levelable, id int) {
+	lgr.Info("start job %d", id)
+	doWorkLine = thisLine() + 1
+	lgr.Error("job %d failed", id)
+}
+
+func TestLogtest(t *testing.T) {
+	lgr := logtest.NewT(t, logtest.WithLevel(logger.LogInfoLevel))
+	var _ logger.Loggable = lgr
+
+	doWork(lgr, 7)
+	lgr.Debug("hidden")
+	line := thisLine() + 1
+	lgr.With(logger.Field{Key: logger.CtxTraceId, Value: "abc"}).ErrorE(errors.New("boom"), "with error")
+
+	entries := lgr.Entries()
+	logtest.AssertCount(t, entries, 3)
+	entry := logtest.AssertLogged(t, entries, logger.LogErrorLevel, `^job \d+ failed$`)
+	if entry.Caller != "logtest_test.go:"+strconv.Itoa(doWorkLine) {
+		t.Errorf("caller of doWork() record: %s", entry.Caller)
+	}
+	logtest.AssertNotLogged(t, entries, logger.LogDebugLevel, ".*")
+
+	traced := entries.FilterTrace("abc")
+	if len(traced) != 1 || traced[0].Caller != "logtest_test.go:"+strconv.Itoa(line) {
+		t.Fatalf("trace filter: %s", traced)
+	}
+	logtest.AssertField(t, traced[0], logger.ErrorKey, "boom")
+	if len(entries.FilterMinLevel(logger.LogErrorLevel)) != 2 || len(entries.AllFields()) != 2 {
+		t.Errorf("filters: %s", entries)
+	}
+
+	func() {
+		defer func() {
+			if _, ok := recover().(logtest.FatalExit); !ok {
+				t.Errorf("Fatal() does not panic with FatalExit")
+			}
+		}()
+		lgr.Fatal("stop")
+	}()
+	lgr.Reset()
+	logtest.AssertNoErrors(t, lgr.Entries())
+}
+
+// TestLogtestDisabledFatal -- Fatal() и Panic() при запрещенном уровне ничего не делают, как у BaseLogger
+func TestLogtestDisabledFatal(t *testing.T) {
+	lgr := logtest.New(logtest.WithLevel(logger.LogNoneLevel))
+	lgr.Fatal("stop")
+	lgr.Panic("boom")
+	logtest.AssertCount(t, lgr.Entries(), 0)
+
+	lgr.SetLevel(logger.LogPanicLevel)
+	lgr.Fatal("stop")
+	func() {
+		defer func() {
+			if value := recover(); value != "boom" {
+				t.Errorf("Panic() on LogPanicLevel: %v", value)
+			}
+		}()
+		lgr.Panic("boom")
+	}()
+	if entries := lgr.Entries(); len(entries) != 1 || entries[0].Level != logger.LogPanicLevel {
+		t.Errorf("entries: %s", entries)
+	}
+}
+
+// TestLogtestSetLevelConcurrent -- смена уровня из теста во время записи из обработчиков, для go test -race
+func TestLogtestSetLevelConcurrent(t *testing.T) {
+	lgr := logtest.New(logtest.WithLevel(logger.LogInfoLevel))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				doWork(lgr.With(logger.Field{Key: "worker", Value: id}), n)
+			}
+		}(i)
+	}
+	for n := 0; n < 100; n++ {
+		lgr.SetLevel(logger.LogErrorLevel)
+		lgr.SetLevel(logger.LogInfoLevel)
+	}
+	wg.Wait()
+
+	if errs := lgr.Entries().FilterLevel(logger.LogErrorLevel); len(errs) != 400 {
+		t.Errorf("error entries: %d", len(errs))
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func TestRecover(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	lgr := logger.BaseLogger{}
+	lgr.Init(&logger.LogConfig{Format: "logfmt", Level: logger.LogInfoLevel}, &err)
+	lgr.Out = out
+
+	var hooks []string
+	lgr.OnExit(func() { hooks = append(hooks, "first") })
+	lgr.OnExit(func() { hooks = append(hooks, "second") })
+
+	func() {
+		defer lgr.Recover()
+		panic("boom")
+	}()
+
+	text := out.String()
+	if !strings.HasPrefix(text, `level=panic msg="recovered panic" panic=boom panic_type=string stacktrace="`) ||
+		!strings.Contains(text, "tests.TestRecover.func") {
+		t.Errorf("recover record: %s", text)
+	}
+	// паника поглощена, процесс работает дальше: завершители не выполнены и остались зарегистрированы
+	if len(hooks) != 0 {
+		t.Errorf("exit hooks after swallow: %v", hooks)
+	}
+
+	defer func() {
+		if value := recover(); value != "again" {
+			t.Errorf("repanic value: %v", value)
+		}
+		if strings.Join(hooks, ",") != "second,first" {
+			t.Errorf("exit hooks: %v", hooks)
+		}
+	}()
+	func() {
+		defer lgr.Recover(logger.WithRecoverMode(logger.RecoverRepanic))
+		panic("again")
+	}()
+	t.Error("must not be here")
+}
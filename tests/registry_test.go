@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Arhat109/logger/pkg/logger"
+)
+
+func TestRegistry(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	root := &logger.BaseLogger{}
+	root.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Flags: logger.LogPrefix, Level: logger.LogWarnLevel}, &err)
+	root.Out = out
+	reg := logger.NewRegistry(root)
+
+	reg.SetFields("db", logger.Field{Key: "component", Value: "db"})
+	pool := reg.Named("db.pool")
+	if pool != reg.Named(" db..pool ") {
+		t.Fatalf("Named() returns different loggers for the same name")
+	}
+
+	pool.Info("hidden")
+	reg.SetLevel("db", logger.LogDebugLevel)
+	pool.Debug("shown")
+	reg.Named("api").Info("hidden too")
+
+	want := "level=debug logger=db.pool msg=shown component=db\n"
+	if got := out.String(); got != want {
+		t.Errorf("output:\n got %q\nwant %q", got, want)
+	}
+
+	// свой вывод узла -- и для потомков, сброс -- снова родительский
+	own := &bytes.Buffer{}
+	reg.SetOut("db", own)
+	pool.Warn("to own")
+	reg.ResetOut("db")
+	pool.Warn("to root")
+	if !strings.Contains(own.String(), "to own") || strings.Contains(out.String(), "to own") || !strings.Contains(out.String(), "to root") {
+		t.Errorf("sink override: own %q, root %q", own.String(), out.String())
+	}
+
+	reg.ResetLevel("db")
+	var names []string
+	levels := map[string]int{}
+	reg.Walk(func(info logger.LoggerInfo) bool {
+		names = append(names, info.Name)
+		levels[info.Name] = info.Level
+		return true
+	})
+	if strings.Join(names, ",") != ",api,db,db.pool" {
+		t.Errorf("walk order: %v", names)
+	}
+	if levels["db.pool"] != logger.LogWarnLevel {
+		t.Errorf("level is not inherited after reset: %v", levels)
+	}
+	if info, ok := reg.Lookup("db.pool"); !ok || info.IsLevelSet || len(reg.List()) != 4 {
+		t.Errorf("lookup: %+v %v", info, ok)
+	}
+}
+
+// TestRegistryConcurrentUpdates -- изменения узлов на ходу во время записи через их логгеры, для go test -race
+func TestRegistryConcurrentUpdates(t *testing.T) {
+	var err error
+	out, own := &bytes.Buffer{}, &bytes.Buffer{}
+	root := &logger.BaseLogger{}
+	root.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Flags: logger.LogPrefix, Level: logger.LogInfoLevel}, &err)
+	root.Out = out
+	reg := logger.NewRegistry(root)
+	pool := reg.Named("db.pool")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 300; n++ {
+				pool.Debug("debug")
+				pool.Info("info")
+				reg.Named("db.pool.conn").With(logger.Field{Key: "conn", Value: n}).Warn("warn")
+				_ = reg.List()
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	rounds := 0
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			rounds++
+			reg.SetLevel("db", logger.LogDebugLevel)
+			reg.SetFields("db", logger.Field{Key: "round", Value: rounds})
+			reg.SetOut("db.pool", own)
+			reg.SetLevel("", logger.LogWarnLevel)
+			reg.ResetOut("db.pool")
+			reg.ResetLevel("db")
+			reg.SetLevel("", logger.LogInfoLevel)
+		}
+	}
+
+	// после всех изменений -- унаследованные уровень и вывод корня
+	pool.Info("final")
+	if pool.GetLevel() != logger.LogInfoLevel || !strings.HasSuffix(out.String(), "level=info logger=db.pool msg=final round="+strconv.Itoa(rounds)+"\n") {
+		t.Errorf("after updates: level %d, output of %d bytes", pool.GetLevel(), out.Len())
+	}
+}
+
+// TestRegistryRootChanges -- замена корня и изменения самого корня во время записи через логгеры узлов
+func TestRegistryRootChanges(t *testing.T) {
+	var err error
+	out := &bytes.Buffer{}
+	first := &logger.BaseLogger{}
+	first.Init(&logger.LogConfig{Out: "devnul", Format: "logfmt", Flags: logger.LogPrefix, Level: logger.LogInfoLevel}, &err)
+	first.Out = out
+	second := &logger.BaseLogger{}
+	second.Init(&logger.LogConfig{Out: "devnul", Format: "json", Flags: logger.LogPrefix, Level: logger.LogInfoLevel,
+		TraceId: "rid"}, &err)
+	second.Out = out
+	reg := logger.NewRegistry(first)
+	pool := reg.Named("db.pool")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 300; n++ {
+				pool.Info("info")
+				reg.Named("db").With(logger.Field{Key: "n", Value: n}).Warn("warn")
+			}
+		}()
+	}
+	for n := 0; n < 100; n++ {
+		reg.SetRoot(second)
+		second.SetLevel(logger.LogWarnLevel)
+		reg.SetRoot(first)
+		first.SetFlags(logger.LogPrefix | logger.LogDate)
+		first.SetFlags(logger.LogPrefix)
+	}
+	wg.Wait()
+
+	// изменения корня напрямую доходят до узлов, формат и ключ трассировки -- от корня
+	out.Reset()
+	first.SetLevel(logger.LogErrorLevel)
+	pool.Warn("hidden")
+	if pool.GetLevel() != logger.LogErrorLevel || out.Len() != 0 {
+		t.Errorf("root level is not inherited: %d, %q", pool.GetLevel(), out.String())
+	}
+	reg.SetRoot(second)
+	second.SetLevel(logger.LogInfoLevel)
+	pool.Info("json")
+	if got := out.String(); !strings.HasSuffix(got, `"message":"json","logger":"db.pool"}`+"\n") || pool.GetTraceKey() != "rid" {
+		t.Errorf("new root format: %q, trace key %q", got, pool.GetTraceKey())
+	}
+	if err = second.Reload(&logger.LogConfig{Level: logger.LogErrorLevel, Flags: logger.LogPrefix}); err != nil {
+		t.Fatal(err)
+	}
+	if pool.GetLevel() != logger.LogErrorLevel {
+		t.Errorf("reloaded root level is not inherited: %d", pool.GetLevel())
+	}
+}